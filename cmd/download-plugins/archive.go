@@ -0,0 +1,128 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// archiveWriter writes plugin ZIPs as entries of a tar.gz archive
+type archiveWriter struct {
+	f  *os.File
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func createArchive(path string) (*archiveWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	gz := gzip.NewWriter(f)
+	return &archiveWriter{f: f, gz: gz, tw: tar.NewWriter(gz)}, nil
+}
+
+// add downloads a plugin to a temporary file and, once it is known to be a
+// complete plugin ZIP, appends it as <slug>.zip. A failed download leaves
+// the archive untouched.
+func (a *archiveWriter) add(ctx context.Context, client *wordpress.Client, plugin wordpress.PluginInfo) (int64, error) {
+	if plugin.DownloadLink == "" {
+		return 0, fmt.Errorf("plugin %q has no download link", plugin.Slug)
+	}
+
+	tmp, err := os.CreateTemp("", "wp-plugin-*.zip")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := client.DownloadPluginTo(ctx, plugin.DownloadLink, tmp)
+	if err != nil {
+		return size, fmt.Errorf("download failed: %w", err)
+	}
+	if err := wordpress.ValidatePluginZipReader(tmp, size, plugin.Slug); err != nil {
+		return size, err
+	}
+
+	hdr := &tar.Header{
+		Name:    plugin.Slug + ".zip",
+		Mode:    0644,
+		Size:    size,
+		ModTime: time.Now(),
+	}
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return size, fmt.Errorf("failed to write archive entry: %w", err)
+	}
+	if _, err := io.Copy(a.tw, io.NewSectionReader(tmp, 0, size)); err != nil {
+		return size, fmt.Errorf("failed to write archive entry: %w", err)
+	}
+
+	return size, nil
+}
+
+// Close flushes the archive and closes the file
+func (a *archiveWriter) Close() error {
+	twErr := a.tw.Close()
+	gzErr := a.gz.Close()
+	fErr := a.f.Close()
+
+	for _, err := range []error{twErr, gzErr, fErr} {
+		if err != nil {
+			return fmt.Errorf("failed to close archive: %w", err)
+		}
+	}
+	return nil
+}
+
+// archivePlugins downloads each plugin into the tar.gz archive at path
+// without extracting it, and returns one result per plugin. pinned reports
+// whether the plugins were resolved from a manifest.
+func archivePlugins(ctx context.Context, client *wordpress.Client, plugins []wordpress.PluginInfo, path string, pinned bool, logger *log.Logger) ([]DownloadResult, error) {
+	archive, err := createArchive(path)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]DownloadResult, 0, len(plugins))
+	for i, plugin := range plugins {
+		// Rate limiting
+		if i > 0 {
+			time.Sleep(500 * time.Millisecond)
+		}
+
+		logger.Printf("[%d/%d] Downloading %s (%s)...", i+1, len(plugins), plugin.Name, plugin.Version)
+
+		result := DownloadResult{
+			Slug:    plugin.Slug,
+			Version: plugin.Version,
+		}
+		size, err := archive.add(ctx, client, plugin)
+		result.Size = size
+		if pinned {
+			err = pinnedDownloadError(err)
+		}
+		if err != nil {
+			logger.Printf("  ⚠️  Failed to download %s: %v", plugin.Slug, err)
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+			result.Path = path
+			logger.Printf("  ✅ Successfully added to %s", path)
+		}
+		results = append(results, result)
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
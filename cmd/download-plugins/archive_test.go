@@ -0,0 +1,101 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestArchivePlugins(t *testing.T) {
+	zips := map[string][]byte{
+		"hello-dolly": buildPluginZip(t, map[string]string{
+			"hello-dolly/hello.php": "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.7.2\n*/",
+		}),
+		"akismet": buildPluginZip(t, map[string]string{
+			"akismet/akismet.php": "<?php\n/*\nPlugin Name: Akismet\nVersion: 5.3\n*/",
+		}),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hello-dolly.zip", "/akismet.zip":
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write(zips[strings.TrimSuffix(path.Base(r.URL.Path), ".zip")])
+		case "/broken.zip":
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write([]byte("not a zip"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	plugins := []wordpress.PluginInfo{
+		{Slug: "hello-dolly", Version: "1.7.2", DownloadLink: server.URL + "/hello-dolly.zip"},
+		{Slug: "missing", Version: "1.0", DownloadLink: server.URL + "/missing.zip"},
+		{Slug: "broken", Version: "1.0", DownloadLink: server.URL + "/broken.zip"},
+		{Slug: "akismet", Version: "5.3", DownloadLink: server.URL + "/akismet.zip"},
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "plugins.tar.gz")
+	results, err := archivePlugins(context.Background(), wordpress.NewClient(), plugins, archivePath, false, discardLogger())
+	if err != nil {
+		t.Fatalf("archivePlugins() error = %v", err)
+	}
+
+	wantSuccess := map[string]bool{"hello-dolly": true, "missing": false, "broken": false, "akismet": true}
+	for _, r := range results {
+		if r.Success != wantSuccess[r.Slug] {
+			t.Errorf("Unexpected result for %s: %+v", r.Slug, r)
+		}
+		if strings.Contains(r.Error, errVersionUnavailable.Error()) {
+			t.Errorf("Expected %s not to be reported unavailable, got %+v", r.Slug, r)
+		}
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("Archive isn't gzipped: %v", err)
+	}
+	tr := tar.NewReader(gz)
+
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Corrupt archive: %v", err)
+		}
+		names = append(names, hdr.Name)
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, zips[strings.TrimSuffix(hdr.Name, ".zip")]) {
+			t.Errorf("Entry %s doesn't match the downloaded ZIP", hdr.Name)
+		}
+	}
+
+	if len(names) != 2 || names[0] != "hello-dolly.zip" || names[1] != "akismet.zip" {
+		t.Errorf("Expected one entry per successful plugin, got %v", names)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// listHeader is the header row of the -list-only CSV output
+var listHeader = []string{"slug", "name", "version", "active_installs", "rating", "last_updated"}
+
+// listPlugins writes the top cfg.Count plugins of the browse set or search
+// in cfg to w without downloading them, as CSV with -format csv or as one
+// "slug (version)" line each otherwise. Rows are written and flushed as each
+// page arrives, so only one page is held in memory.
+func listPlugins(ctx context.Context, client *wordpress.Client, cfg Config, w io.Writer) error {
+	var write func(wordpress.PluginInfo) error
+	flush := func() error { return nil }
+	if cfg.Format == formatCSV {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(listHeader); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+		write = func(p wordpress.PluginInfo) error {
+			return cw.Write(listRecord(p))
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	} else {
+		write = func(p wordpress.PluginInfo) error {
+			_, err := fmt.Fprintf(w, "%s (%s)\n", p.Slug, p.Version)
+			return err
+		}
+	}
+
+	perPage := pageSize(cfg.Count, cfg.PerPage)
+	listed := 0
+	for page := 1; listed < cfg.Count; page++ {
+		var (
+			resp *wordpress.QueryPluginsResponse
+			err  error
+		)
+		if cfg.Search != "" {
+			resp, err = client.SearchPlugins(ctx, cfg.Search, perPage, page)
+		} else {
+			resp, err = client.QueryPlugins(ctx, cfg.Browse, perPage, page)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to query plugins: %w", err)
+		}
+
+		for _, p := range resp.Plugins[:min(len(resp.Plugins), cfg.Count-listed)] {
+			if err := write(p); err != nil {
+				return fmt.Errorf("failed to write %s: %w", p.Slug, err)
+			}
+			listed++
+		}
+		if err := flush(); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+
+		if len(resp.Plugins) == 0 || !resp.HasNextPage() {
+			break
+		}
+	}
+
+	return nil
+}
+
+// listRecord returns the CSV row of p, matching listHeader
+func listRecord(p wordpress.PluginInfo) []string {
+	lastUpdated := ""
+	if !p.LastUpdated.IsZero() {
+		lastUpdated = p.LastUpdated.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		p.Slug,
+		p.Name,
+		p.Version,
+		strconv.Itoa(p.ActiveInstalls),
+		strconv.FormatFloat(float64(p.Rating), 'f', -1, 64),
+		lastUpdated,
+	}
+}
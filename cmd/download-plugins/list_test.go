@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestListPlugins_CSV(t *testing.T) {
+	pages := map[string]string{
+		"1": `{"info": {"page": 1, "pages": 2, "results": 3}, "plugins": [
+			{"slug": "akismet", "name": "Akismet Anti-spam", "version": "5.5", "active_installs": 6000000, "rating": 94, "last_updated": "2025-01-02 3:04pm GMT"},
+			{"slug": "contact-form-7", "name": "Contact Form 7", "version": "6.1.3", "active_installs": 10000000, "rating": 82.5, "last_updated": ""}
+		]}`,
+		"2": `{"info": {"page": 2, "pages": 2, "results": 3}, "plugins": [
+			{"slug": "hello-dolly", "name": "Hello Dolly, the \"classic\"", "version": "1.7.2", "active_installs": 100, "rating": 50}
+		]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("request[browse]"); got != "popular" {
+			t.Errorf("Expected the popular browse set, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pages[r.URL.Query().Get("request[page]")]))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+	cfg := Config{Count: 10, Browse: wordpress.BrowsePopular, Format: formatCSV, ListOnly: true}
+
+	var buf bytes.Buffer
+	if err := listPlugins(context.Background(), client, cfg, &buf); err != nil {
+		t.Fatalf("listPlugins() error = %v", err)
+	}
+
+	want := strings.Join([]string{
+		"slug,name,version,active_installs,rating,last_updated",
+		"akismet,Akismet Anti-spam,5.5,6000000,94,2025-01-02T15:04:00Z",
+		"contact-form-7,Contact Form 7,6.1.3,10000000,82.5,",
+		`hello-dolly,"Hello Dolly, the ""classic""",1.7.2,100,50,`,
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("listPlugins() wrote\n%s\nwant\n%s", got, want)
+	}
+
+	// The count cuts the listing short
+	buf.Reset()
+	cfg.Count = 1
+	if err := listPlugins(context.Background(), client, cfg, &buf); err != nil {
+		t.Fatalf("listPlugins() error = %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 2 {
+		t.Errorf("Expected the header and 1 row, got %q", lines)
+	}
+}
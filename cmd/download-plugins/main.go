@@ -1,168 +1,530 @@
 package main
 
 import (
-	"archive/zip"
-	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/masahiro331/go-wp-detector/pkg/downloader"
 	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
 )
 
 const (
 	defaultOutputDir = "testdata/wp-content/plugins"
+	// maxPerPage is the largest page size the plugins API accepts
+	maxPerPage = 100
+	// pageInterval is the least time between two plugin list pages
+	pageInterval = time.Second
+
+	formatText = "text"
+	formatJSON = "json"
+	formatCSV  = "csv"
 )
 
 type Config struct {
 	Count     int
 	OutputDir string
+	Format    string
+	DryRun    bool
+	Layout    downloader.Layout
+	Checksums bool
+	// SkipExisting skips plugins already extracted with the same version
+	SkipExisting bool
+	// Limits guards extraction against ZIP bombs
+	Limits downloader.Limits
+	// Manifest is a file of pinned slug=version lines to download instead
+	// of the popular plugins
+	Manifest string
+	// Browse is the browse set to download the top plugins of
+	Browse string
+	// Search downloads the top matches for a keyword instead of a browse set
+	Search string
+	// FromStdin downloads the latest version of the slugs read from stdin
+	FromStdin bool
+	// Archive writes the plugin ZIPs into this tar.gz file instead of
+	// extracting them
+	Archive string
+	// ListOnly lists the plugins of the browse set or search without
+	// downloading them
+	ListOnly bool
+	// PerPage is the page size of plugin queries, at most maxPerPage; zero
+	// uses maxPerPage
+	PerPage int
+	// PreserveTimes gives extracted files the modification times recorded
+	// in the archive
+	PreserveTimes bool
+	// FileMode and DirMode force the permissions of extracted files and
+	// directories; zero keeps the archive's, less the umask
+	FileMode os.FileMode
+	DirMode  os.FileMode
+}
+
+// DownloadResult describes the outcome of downloading a single plugin
+type DownloadResult struct {
+	Slug    string `json:"slug"`
+	Version string `json:"version"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Size    int64  `json:"size"`
+	// Skipped is set when the plugin was already extracted
+	Skipped bool `json:"skipped,omitempty"`
 }
 
 func main() {
-	cfg := parseFlags()
+	cfg, err := parseFlags(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
 
 	if err := run(cfg); err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 }
 
-func parseFlags() Config {
+func parseFlags(args []string) (Config, error) {
 	var cfg Config
 
-	flag.IntVar(&cfg.Count, "count", 100, "Number of plugins to download")
-	flag.StringVar(&cfg.OutputDir, "output", defaultOutputDir, "Output directory for plugins")
-	flag.Parse()
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fs.IntVar(&cfg.Count, "count", 100, "Number of plugins to download")
+	fs.IntVar(&cfg.PerPage, "per-page", maxPerPage, fmt.Sprintf("Plugins requested per API page (1-%d)", maxPerPage))
+	fs.StringVar(&cfg.OutputDir, "output", defaultOutputDir, "Output directory for plugins")
+	fs.StringVar(&cfg.Format, "format", formatText, "Output format (text|json, or csv with -list-only)")
+	fs.BoolVar(&cfg.DryRun, "dry-run", false, "List plugins and their estimated size without downloading")
+	fs.BoolVar(&cfg.Checksums, "checksums", false, "Write a SHA-256 manifest (checksums.txt) for each extracted plugin")
+	fs.BoolVar(&cfg.SkipExisting, "skip-existing", false, "Skip plugins already extracted with the same version")
+	fs.StringVar(&cfg.Manifest, "manifest", "", "Download the exact versions pinned in this file of slug=version lines")
+	fs.StringVar(&cfg.Browse, "browse", wordpress.BrowsePopular, "Browse set to download the top plugins of (popular|featured|updated|new|beta|blocks)")
+	fs.StringVar(&cfg.Search, "search", "", "Download the top matches for this keyword instead of a browse set")
+	fs.StringVar(&cfg.Archive, "archive", "", "Write the plugin ZIPs into this tar.gz file instead of extracting them")
+	fs.BoolVar(&cfg.FromStdin, "from-stdin", false, "Download the latest version of the newline-separated slugs read from stdin")
+	fs.BoolVar(&cfg.ListOnly, "list-only", false, "List the plugins of the browse set or search without downloading them")
+	fs.BoolVar(&cfg.PreserveTimes, "preserve-times", true, "Keep the modification times recorded in the plugin archives")
+	fileMode := fs.String("file-mode", "", "Force the permissions of extracted files, in octal (e.g. 0644)")
+	dirMode := fs.String("dir-mode", "", "Force the permissions of extracted directories, in octal (e.g. 0755)")
+	layout := fs.String("layout", string(downloader.LayoutSlug), "Extraction layout (slug|versioned|flat)")
+	maxFileSize := fs.Int64("max-file-size", downloader.DefaultMaxFileSize>>20, "Largest uncompressed file to extract, in MiB")
+	maxTotalSize := fs.Int64("max-total-size", downloader.DefaultMaxTotalSize>>20, "Largest uncompressed plugin to extract, in MiB")
+	fs.IntVar(&cfg.Limits.MaxFiles, "max-files", downloader.DefaultMaxFiles, "Largest number of files in a plugin archive")
+	fs.Parse(args)
+
+	cfg.Layout = downloader.Layout(*layout)
+	cfg.Limits.MaxFileSize = *maxFileSize << 20
+	cfg.Limits.MaxTotalSize = *maxTotalSize << 20
+
+	if cfg.Count < 1 {
+		return cfg, fmt.Errorf("-count must be at least 1")
+	}
+	if cfg.PerPage < 1 || cfg.PerPage > maxPerPage {
+		return cfg, fmt.Errorf("-per-page must be between 1 and %d", maxPerPage)
+	}
 
-	return cfg
+	var err error
+	if cfg.FileMode, err = parseMode(*fileMode); err != nil {
+		return cfg, fmt.Errorf("invalid -file-mode: %w", err)
+	}
+	if cfg.DirMode, err = parseMode(*dirMode); err != nil {
+		return cfg, fmt.Errorf("invalid -dir-mode: %w", err)
+	}
+
+	if cfg.Search != "" {
+		browseSet := false
+		fs.Visit(func(f *flag.Flag) {
+			browseSet = browseSet || f.Name == "browse"
+		})
+		if browseSet {
+			return cfg, fmt.Errorf("-search and -browse can't be used together")
+		}
+		cfg.Browse = ""
+	}
+
+	return cfg, nil
 }
 
 func run(cfg Config) error {
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	switch {
+	case cfg.ListOnly && cfg.Format == formatJSON:
+		return fmt.Errorf("-list-only supports the text and csv formats")
+	case cfg.ListOnly && (cfg.Manifest != "" || cfg.FromStdin):
+		return fmt.Errorf("-list-only can't be used with -manifest or -from-stdin")
+	case cfg.Format == formatCSV && !cfg.ListOnly:
+		return fmt.Errorf("the csv format requires -list-only")
+	case cfg.Format != formatText && cfg.Format != formatJSON && cfg.Format != formatCSV:
+		return fmt.Errorf("unknown format %q", cfg.Format)
+	}
+	if _, err := downloader.ParseLayout(string(cfg.Layout)); err != nil {
+		return err
+	}
+	// Plugins share the output directory in the flat layout, so there is no
+	// per-plugin directory to check
+	if cfg.SkipExisting && cfg.Layout == downloader.LayoutFlat {
+		return fmt.Errorf("-skip-existing can't be used with the flat layout")
+	}
+	// Nothing is extracted into an archive
+	if cfg.Archive != "" && (cfg.SkipExisting || cfg.Checksums) {
+		return fmt.Errorf("-archive can't be used with -skip-existing or -checksums")
+	}
+
+	// In JSON mode the summary on stdout is the only output
+	logger := log.Default()
+	if cfg.Format == formatJSON {
+		logger = log.New(io.Discard, "", 0)
 	}
 
 	client := wordpress.NewClient()
+	pager := newPager(cfg, logger)
 	ctx := context.Background()
 
-	log.Printf("Fetching top %d popular plugins from WordPress.org...", cfg.Count)
+	if cfg.ListOnly {
+		return listPlugins(ctx, pager, cfg, os.Stdout)
+	}
 
-	// Calculate pagination
-	const perPage = 100
-	totalPages := (cfg.Count + perPage - 1) / perPage
+	var (
+		allPlugins  []wordpress.PluginInfo
+		unavailable []DownloadResult
+	)
+	if cfg.Manifest != "" {
+		entries, err := readManifest(cfg.Manifest)
+		if err != nil {
+			return err
+		}
+		logger.Printf("Resolving %d pinned plugins from %s...", len(entries), cfg.Manifest)
+		allPlugins, unavailable, err = resolveManifest(ctx, pager, entries, logger)
+		if err != nil {
+			return err
+		}
+	} else if cfg.FromStdin {
+		slugs, err := readSlugs(os.Stdin)
+		if err != nil {
+			return err
+		}
+		logger.Printf("Looking up %d plugins read from stdin...", len(slugs))
+		allPlugins, unavailable, err = resolveSlugs(ctx, pager, slugs, logger)
+		if err != nil {
+			return err
+		}
+	} else if cfg.Search != "" {
+		var err error
+		allPlugins, err = searchPlugins(ctx, pager, cfg.Search, cfg.Count, cfg.PerPage, logger)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		allPlugins, err = fetchPlugins(ctx, pager, cfg.Browse, cfg.Count, cfg.PerPage, logger)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.DryRun {
+		logger.Printf("Found %d plugins. Estimating download size...", len(allPlugins))
+		_, _, err := estimateDownloads(ctx, client, allPlugins, os.Stdout)
+		return err
+	}
 
-	var allPlugins []wordpress.PluginInfo
+	logger.Printf("Found %d plugins. Starting download...", len(allPlugins))
 
-	for page := 1; page <= totalPages; page++ {
-		requestPerPage := perPage
-		if page == totalPages {
-			// Last page might need fewer plugins
-			remaining := cfg.Count - len(allPlugins)
-			if remaining < perPage {
-				requestPerPage = remaining
-			}
+	var results []DownloadResult
+	dest := cfg.OutputDir
+	if cfg.Archive != "" {
+		dest = cfg.Archive
+		archived, err := archivePlugins(ctx, client, allPlugins, cfg.Archive, cfg.Manifest != "", logger)
+		if err != nil {
+			return err
+		}
+		results = append(unavailable, archived...)
+	} else {
+		// Create output directory if it doesn't exist
+		if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
 		}
+		results = append(unavailable, downloadPlugins(ctx, client, allPlugins, cfg, logger)...)
+	}
 
-		log.Printf("Fetching page %d/%d (per_page=%d)...", page, totalPages, requestPerPage)
+	if cfg.Format == formatJSON {
+		return writeResults(os.Stdout, results)
+	}
 
-		resp, err := client.QueryPlugins(ctx, "popular", requestPerPage, page)
-		if err != nil {
-			return fmt.Errorf("failed to query plugins: %w", err)
+	skipped := 0
+	var missing, unknown []string
+	for _, r := range results {
+		if r.Skipped {
+			skipped++
 		}
+		switch {
+		case strings.HasPrefix(r.Error, errVersionUnavailable.Error()):
+			missing = append(missing, r.Slug+" "+r.Version)
+		case r.Error == errPluginNotFound.Error():
+			unknown = append(unknown, r.Slug)
+		}
+	}
+	logger.Printf("\n✅ Download complete! %d plugins saved to %s (%d already present)", len(allPlugins), dest, skipped)
+	if len(missing) > 0 {
+		logger.Printf("⚠️  No longer available: %s", strings.Join(missing, ", "))
+	}
+	if len(unknown) > 0 {
+		logger.Printf("⚠️  Not found on WordPress.org: %s", strings.Join(unknown, ", "))
+	}
 
-		allPlugins = append(allPlugins, resp.Plugins...)
+	return nil
+}
 
-		if len(allPlugins) >= cfg.Count {
-			allPlugins = allPlugins[:cfg.Count]
-			break
+// newPager returns the client plugin lists are paged through, whether
+// browsed, searched or listed, and that stdin slugs and manifest versions
+// are looked up with. Requests are made at most one per pageInterval to be
+// respectful to the WordPress.org API, and each page of a browse set is
+// logged.
+func newPager(cfg Config, logger *log.Logger) *wordpress.Client {
+	perPage := pageSize(cfg.Count, cfg.PerPage)
+	totalPages := (cfg.Count + perPage - 1) / perPage
+
+	return wordpress.NewClient(
+		// Large counts take many pages; a transient failure shouldn't lose them
+		wordpress.WithPageRetry(3),
+		wordpress.WithRateLimit(1/pageInterval.Seconds(), 1),
+		// QueryAllPlugins doesn't report its pages, so they are logged as
+		// they are received
+		wordpress.WithResponseInterceptor(func(method string, body []byte) {
+			if method != "QueryPlugins" {
+				return
+			}
+			var page struct {
+				Info wordpress.QueryInfo `json:"info"`
+			}
+			if err := json.Unmarshal(body, &page); err == nil {
+				logger.Printf("Fetched page %d/%d (per_page=%d)", page.Info.Page, min(page.Info.Pages, totalPages), perPage)
+			}
+		}),
+	)
+}
+
+// pageSize returns the per_page to request when fetching count plugins in
+// pages of perPage, so that a small count takes a single small page. It is
+// always at least 1.
+func pageSize(count, perPage int) int {
+	if perPage <= 0 {
+		perPage = maxPerPage
+	}
+	return max(1, min(count, perPage))
+}
+
+// fetchPlugins queries the top count plugins of a browse set from
+// WordPress.org
+func fetchPlugins(ctx context.Context, client *wordpress.Client, browse string, count, perPage int, logger *log.Logger) ([]wordpress.PluginInfo, error) {
+	logger.Printf("Fetching top %d %s plugins from WordPress.org...", count, browse)
+
+	// A fixed page size keeps the pages aligned; QueryAllPlugins trims the
+	// last one and stops at the end of the set
+	allPlugins, err := client.QueryAllPlugins(ctx, browse, pageSize(count, perPage), count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query plugins: %w", err)
+	}
+	if len(allPlugins) < count {
+		logger.Printf("⚠️  Only %d %s plugins are available, the API doesn't list more", len(allPlugins), browse)
+	}
+
+	return allPlugins, nil
+}
+
+// searchPlugins returns the top count matches for keyword on WordPress.org
+func searchPlugins(ctx context.Context, client *wordpress.Client, keyword string, count, perPage int, logger *log.Logger) ([]wordpress.PluginInfo, error) {
+	logger.Printf("Searching WordPress.org for the top %d matches for %q...", count, keyword)
+
+	perPage = pageSize(count, perPage)
+
+	var plugins []wordpress.PluginInfo
+	for page := 1; len(plugins) < count; page++ {
+		resp, err := client.SearchPlugins(ctx, keyword, perPage, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search plugins: %w", err)
 		}
+		logger.Printf("Fetched page %d/%d (per_page=%d)", page, min(resp.TotalPages(), (count+perPage-1)/perPage), perPage)
 
-		// Rate limiting - be respectful to WordPress.org API
-		time.Sleep(1 * time.Second)
+		plugins = append(plugins, resp.Plugins...)
+		if !resp.HasNextPage() {
+			break
+		}
 	}
 
-	log.Printf("Found %d plugins. Starting download...", len(allPlugins))
+	if len(plugins) > count {
+		plugins = plugins[:count]
+	}
+	return plugins, nil
+}
 
-	// Download and extract plugins
-	for i, plugin := range allPlugins {
-		log.Printf("[%d/%d] Downloading %s (%s)...", i+1, len(allPlugins), plugin.Name, plugin.Version)
+// estimateDownloads reports the size of each plugin's download without
+// fetching it and writes a summary to w. Plugins whose size can't be
+// determined are counted in unknown.
+func estimateDownloads(ctx context.Context, client *wordpress.Client, plugins []wordpress.PluginInfo, w io.Writer) (total int64, unknown int, err error) {
+	for _, plugin := range plugins {
+		size, err := client.HeadDownload(ctx, plugin.DownloadLink)
+		if err != nil {
+			if ctx.Err() != nil {
+				return total, unknown, ctx.Err()
+			}
+			size = -1
+		}
 
-		if err := downloadAndExtractPlugin(ctx, client, plugin, cfg.OutputDir); err != nil {
-			log.Printf("  ⚠️  Failed to download %s: %v", plugin.Slug, err)
+		if size < 0 {
+			unknown++
+			fmt.Fprintf(w, "%s (%s): unknown size\n", plugin.Slug, plugin.Version)
 			continue
 		}
 
-		log.Printf("  ✅ Successfully extracted to %s/%s", cfg.OutputDir, plugin.Slug)
+		total += size
+		fmt.Fprintf(w, "%s (%s): %d bytes\n", plugin.Slug, plugin.Version, size)
+	}
+
+	fmt.Fprintf(w, "\nTotal: %d files, %d bytes estimated", len(plugins), total)
+	if unknown > 0 {
+		fmt.Fprintf(w, " (%d of unknown size)", unknown)
+	}
+	fmt.Fprintln(w)
+
+	return total, unknown, nil
+}
+
+// downloadPlugins downloads and extracts each plugin into cfg.OutputDir
+// using cfg.Layout, logging progress to logger, and returns one result per
+// plugin
+func downloadPlugins(ctx context.Context, client *wordpress.Client, plugins []wordpress.PluginInfo, cfg Config, logger *log.Logger) []DownloadResult {
+	results := make([]DownloadResult, 0, len(plugins))
+
+	downloaded := 0
+	for i, plugin := range plugins {
+		result := DownloadResult{
+			Slug:    plugin.Slug,
+			Version: plugin.Version,
+		}
+
+		dir := cfg.Layout.Dir(cfg.OutputDir, plugin.Slug, plugin.Version)
+		if cfg.SkipExisting && plugin.Version != "" && installedVersion(dir) == plugin.Version {
+			logger.Printf("[%d/%d] Skipping %s (%s): already extracted", i+1, len(plugins), plugin.Name, plugin.Version)
+			result.Success = true
+			result.Skipped = true
+			result.Path = dir
+			results = append(results, result)
+			continue
+		}
 
 		// Rate limiting
-		if i < len(allPlugins)-1 {
+		if downloaded > 0 {
 			time.Sleep(500 * time.Millisecond)
 		}
-	}
+		downloaded++
 
-	log.Printf("\n✅ Download complete! %d plugins saved to %s", len(allPlugins), cfg.OutputDir)
+		logger.Printf("[%d/%d] Downloading %s (%s)...", i+1, len(plugins), plugin.Name, plugin.Version)
 
-	return nil
+		size, err := downloadAndExtractPlugin(ctx, client, plugin, cfg)
+		result.Size = size
+		if cfg.Manifest != "" {
+			err = pinnedDownloadError(err)
+		}
+		if err != nil {
+			logger.Printf("  ⚠️  Failed to download %s: %v", plugin.Slug, err)
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+			result.Path = dir
+			logger.Printf("  ✅ Successfully extracted to %s", result.Path)
+		}
+		results = append(results, result)
+	}
+
+	return results
 }
 
-func downloadAndExtractPlugin(ctx context.Context, client *wordpress.Client, plugin wordpress.PluginInfo, outputDir string) error {
-	// Download plugin ZIP
-	data, err := client.DownloadPlugin(ctx, plugin.DownloadLink)
+// installedVersion returns the version in the plugin header of the plugin
+// extracted to dir, or "" if dir doesn't contain a plugin
+func installedVersion(dir string) string {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("download failed: %w", err)
+		return ""
 	}
 
-	// Extract ZIP
-	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
-	if err != nil {
-		return fmt.Errorf("failed to read ZIP: %w", err)
-	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".php" {
+			continue
+		}
 
-	// Extract all files
-	for _, file := range zipReader.File {
-		if err := extractFile(file, outputDir); err != nil {
-			return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		header, err := wordpress.ReadPluginHeader(f)
+		f.Close()
+		if err == nil {
+			return header.Version
 		}
 	}
 
-	return nil
+	return ""
 }
 
-func extractFile(file *zip.File, outputDir string) error {
-	// Prevent path traversal attacks
-	filePath := filepath.Join(outputDir, file.Name)
-	if !filepath.HasPrefix(filePath, filepath.Clean(outputDir)+string(os.PathSeparator)) {
-		return fmt.Errorf("invalid file path: %s", file.Name)
+// parseMode parses octal permission bits such as "0644". An empty string
+// returns zero.
+func parseMode(s string) (os.FileMode, error) {
+	if s == "" {
+		return 0, nil
 	}
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode == 0 || mode > 0777 {
+		return 0, fmt.Errorf("%q is not a permission mode between 0001 and 0777", s)
+	}
+	return os.FileMode(mode), nil
+}
 
-	if file.FileInfo().IsDir() {
-		return os.MkdirAll(filePath, file.Mode())
+// downloadAndExtractPlugin downloads and extracts a single plugin, writing
+// its checksums manifest when cfg.Checksums is set
+func downloadAndExtractPlugin(ctx context.Context, client *wordpress.Client, plugin wordpress.PluginInfo, cfg Config) (int64, error) {
+	opts := []downloader.ExtractOption{
+		downloader.WithLayout(cfg.Layout, plugin.Version),
+		downloader.WithLimits(cfg.Limits),
+		downloader.WithPreserveTimes(cfg.PreserveTimes),
+	}
+	if cfg.FileMode != 0 {
+		opts = append(opts, downloader.WithFileMode(cfg.FileMode))
+	}
+	if cfg.DirMode != 0 {
+		opts = append(opts, downloader.WithDirMode(cfg.DirMode))
 	}
 
-	// Create parent directory
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		return err
+	var sums downloader.Checksums
+	if cfg.Checksums {
+		sums = make(downloader.Checksums)
+		opts = append(opts, downloader.WithChecksums(sums))
 	}
 
-	// Extract file
-	rc, err := file.Open()
-	if err != nil {
-		return err
+	size, err := downloader.DownloadAndExtract(ctx, client, plugin, cfg.OutputDir, opts...)
+	if err != nil || sums == nil {
+		return size, err
 	}
-	defer rc.Close()
 
-	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
-	if err != nil {
-		return err
+	return size, sums.WriteFile(checksumsPath(cfg, plugin))
+}
+
+// checksumsPath returns where a plugin's checksums manifest is written: in
+// the plugin directory, or next to the files named after the slug in the
+// flat layout where plugins share a directory
+func checksumsPath(cfg Config, plugin wordpress.PluginInfo) string {
+	if cfg.Layout == downloader.LayoutFlat {
+		return filepath.Join(cfg.OutputDir, plugin.Slug+".checksums.txt")
 	}
-	defer f.Close()
+	return filepath.Join(cfg.Layout.Dir(cfg.OutputDir, plugin.Slug, plugin.Version), "checksums.txt")
+}
 
-	_, err = io.Copy(f, rc)
-	return err
+// writeResults writes the download results as a single JSON array
+func writeResults(w io.Writer, results []DownloadResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
 }
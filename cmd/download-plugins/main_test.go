@@ -0,0 +1,393 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/downloader"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// buildPluginZip creates an in-memory plugin archive from a map of file
+// names to contents
+func buildPluginZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func discardLogger() *log.Logger {
+	return log.New(io.Discard, "", 0)
+}
+
+func TestDownloadPlugins(t *testing.T) {
+	helloZip := buildPluginZip(t, map[string]string{
+		"hello-dolly/hello.php": "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.7.2\n*/",
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hello-dolly.zip":
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write(helloZip)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	plugins := []wordpress.PluginInfo{
+		{Slug: "hello-dolly", Version: "1.7.2", DownloadLink: server.URL + "/hello-dolly.zip"},
+		{Slug: "missing", Version: "1.0", DownloadLink: server.URL + "/missing.zip"},
+	}
+
+	outputDir := t.TempDir()
+	cfg := Config{OutputDir: outputDir, Layout: downloader.LayoutSlug}
+	results := downloadPlugins(context.Background(), wordpress.NewClient(), plugins, cfg, discardLogger())
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	ok := results[0]
+	if !ok.Success || ok.Error != "" {
+		t.Errorf("Expected hello-dolly to succeed, got %+v", ok)
+	}
+	if ok.Size != int64(len(helloZip)) {
+		t.Errorf("Expected size %d, got %d", len(helloZip), ok.Size)
+	}
+	if ok.Path != filepath.Join(outputDir, "hello-dolly") {
+		t.Errorf("Unexpected path %s", ok.Path)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "hello-dolly", "hello.php")); err != nil {
+		t.Errorf("Expected extracted file: %v", err)
+	}
+
+	failed := results[1]
+	if failed.Success || failed.Error == "" || failed.Path != "" {
+		t.Errorf("Expected missing to fail, got %+v", failed)
+	}
+	// Only versions pinned in a manifest are reported as unavailable
+	if strings.Contains(failed.Error, errVersionUnavailable.Error()) {
+		t.Errorf("Expected missing not to be reported unavailable, got %+v", failed)
+	}
+}
+
+func TestDownloadPlugins_Checksums(t *testing.T) {
+	helloPHP := "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.7.2\n*/"
+	readme := "=== Hello Dolly ==="
+	helloZip := buildPluginZip(t, map[string]string{
+		"hello-dolly/hello.php":  helloPHP,
+		"hello-dolly/readme.txt": readme,
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(helloZip)
+	}))
+	defer server.Close()
+
+	plugins := []wordpress.PluginInfo{
+		{Slug: "hello-dolly", Version: "1.7.2", DownloadLink: server.URL + "/hello-dolly.zip"},
+	}
+
+	tests := []struct {
+		layout   downloader.Layout
+		manifest string
+	}{
+		{downloader.LayoutSlug, "hello-dolly/checksums.txt"},
+		{downloader.LayoutVersioned, "hello-dolly/1.7.2/checksums.txt"},
+		{downloader.LayoutFlat, "hello-dolly.checksums.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.layout), func(t *testing.T) {
+			outputDir := t.TempDir()
+			cfg := Config{OutputDir: outputDir, Layout: tt.layout, Checksums: true}
+
+			results := downloadPlugins(context.Background(), wordpress.NewClient(), plugins, cfg, discardLogger())
+			if !results[0].Success {
+				t.Fatalf("Expected download to succeed, got %+v", results[0])
+			}
+
+			got, err := os.ReadFile(filepath.Join(outputDir, tt.manifest))
+			if err != nil {
+				t.Fatalf("Expected checksums manifest: %v", err)
+			}
+
+			helloSum := sha256.Sum256([]byte(helloPHP))
+			readmeSum := sha256.Sum256([]byte(readme))
+			want := fmt.Sprintf("%x  hello.php\n%x  readme.txt\n", helloSum, readmeSum)
+			if string(got) != want {
+				t.Errorf("checksums.txt =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestDownloadPlugins_SkipExisting(t *testing.T) {
+	requests := 0
+	helloZip := buildPluginZip(t, map[string]string{
+		"hello-dolly/hello.php": "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.7.2\n*/",
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(helloZip)
+	}))
+	defer server.Close()
+
+	outputDir := t.TempDir()
+	existing := filepath.Join(outputDir, "akismet")
+	if err := os.MkdirAll(existing, 0755); err != nil {
+		t.Fatal(err)
+	}
+	header := "<?php\n/*\nPlugin Name: Akismet Anti-spam\nVersion: 5.5\n*/"
+	if err := os.WriteFile(filepath.Join(existing, "akismet.php"), []byte(header), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins := []wordpress.PluginInfo{
+		{Slug: "akismet", Version: "5.5", DownloadLink: server.URL + "/akismet.zip"},
+		{Slug: "hello-dolly", Version: "1.7.2", DownloadLink: server.URL + "/hello-dolly.zip"},
+	}
+	cfg := Config{OutputDir: outputDir, Layout: downloader.LayoutSlug, SkipExisting: true}
+
+	results := downloadPlugins(context.Background(), wordpress.NewClient(), plugins, cfg, discardLogger())
+
+	if !results[0].Skipped || !results[0].Success || results[0].Path != existing {
+		t.Errorf("Expected akismet to be skipped, got %+v", results[0])
+	}
+	if results[1].Skipped || !results[1].Success {
+		t.Errorf("Expected hello-dolly to be downloaded, got %+v", results[1])
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 download request, got %d", requests)
+	}
+
+	// A different version on disk is downloaded again
+	plugins[0].Version = "5.6"
+	results = downloadPlugins(context.Background(), wordpress.NewClient(), plugins[:1], cfg, discardLogger())
+	if results[0].Skipped {
+		t.Errorf("Expected akismet 5.6 not to be skipped, got %+v", results[0])
+	}
+}
+
+func TestWriteResults(t *testing.T) {
+	results := []DownloadResult{
+		{Slug: "akismet", Version: "5.5", Success: true, Path: "out/akismet", Size: 123},
+		{Slug: "missing", Version: "1.0", Error: "download failed"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeResults(&buf, results); err != nil {
+		t.Fatalf("writeResults() error = %v", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not a JSON array: %v\n%s", err, buf.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(got))
+	}
+	if got[0]["slug"] != "akismet" || got[0]["success"] != true || got[0]["size"] != float64(123) {
+		t.Errorf("Unexpected first entry: %v", got[0])
+	}
+	if got[1]["success"] != false || got[1]["error"] != "download failed" {
+		t.Errorf("Unexpected second entry: %v", got[1])
+	}
+}
+
+func TestEstimateDownloads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected HEAD request, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/akismet.zip":
+			w.Header().Set("Content-Length", "1000")
+		case "/jetpack.zip":
+			w.Header().Set("Content-Length", "2500")
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	plugins := []wordpress.PluginInfo{
+		{Slug: "akismet", Version: "5.5", DownloadLink: server.URL + "/akismet.zip"},
+		{Slug: "jetpack", Version: "15.1", DownloadLink: server.URL + "/jetpack.zip"},
+		{Slug: "mirror-only", Version: "1.0", DownloadLink: server.URL + "/mirror-only.zip"},
+	}
+
+	var buf bytes.Buffer
+	total, unknown, err := estimateDownloads(context.Background(), wordpress.NewClient(), plugins, &buf)
+	if err != nil {
+		t.Fatalf("estimateDownloads() error = %v", err)
+	}
+
+	if total != 3500 {
+		t.Errorf("Expected total 3500, got %d", total)
+	}
+	if unknown != 1 {
+		t.Errorf("Expected 1 unknown size, got %d", unknown)
+	}
+	if !strings.Contains(buf.String(), "Total: 3 files, 3500 bytes estimated (1 of unknown size)") {
+		t.Errorf("Unexpected summary:\n%s", buf.String())
+	}
+}
+
+func TestParseFlags_SearchAndBrowse(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantBrowse string
+		wantSearch string
+		wantErr    bool
+	}{
+		{
+			name:       "default",
+			args:       nil,
+			wantBrowse: wordpress.BrowsePopular,
+		},
+		{
+			name:       "browse",
+			args:       []string{"-browse", "new"},
+			wantBrowse: wordpress.BrowseNew,
+		},
+		{
+			name:       "search replaces the default browse set",
+			args:       []string{"-search", "gallery", "-count", "5"},
+			wantSearch: "gallery",
+		},
+		{
+			name:    "search and browse",
+			args:    []string{"-search", "gallery", "-browse", "popular"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cfg.Browse != tt.wantBrowse || cfg.Search != tt.wantSearch {
+				t.Errorf("parseFlags() browse = %q, search = %q, want %q, %q", cfg.Browse, cfg.Search, tt.wantBrowse, tt.wantSearch)
+			}
+		})
+	}
+}
+
+func TestParseFlags_Modes(t *testing.T) {
+	cfg, err := parseFlags([]string{"-file-mode", "0644", "-dir-mode", "750"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if cfg.FileMode != 0644 || cfg.DirMode != 0750 {
+		t.Errorf("Expected modes 0644 and 0750, got %o and %o", cfg.FileMode, cfg.DirMode)
+	}
+
+	for _, mode := range []string{"644x", "0", "01777"} {
+		if _, err := parseFlags([]string{"-file-mode", mode}); err == nil {
+			t.Errorf("Expected an error for -file-mode %s", mode)
+		}
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		count   int
+		perPage int
+		want    int
+	}{
+		{count: 100, perPage: 100, want: 100},
+		{count: 250, perPage: 100, want: 100},
+		{count: 200, perPage: 50, want: 50},
+		{count: 5, perPage: 100, want: 5},
+		{count: 10, perPage: 0, want: 10},
+		{count: 0, perPage: 100, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := pageSize(tt.count, tt.perPage); got != tt.want {
+			t.Errorf("pageSize(%d, %d) = %d, want %d", tt.count, tt.perPage, got, tt.want)
+		}
+	}
+}
+
+func TestFetchPlugins_SinglePage(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		pages = append(pages, query.Get("request[page]")+"/"+query.Get("request[per_page]"))
+
+		resp := wordpress.QueryPluginsResponse{Info: wordpress.QueryInfo{Page: 1, Pages: 5, Results: 500}}
+		for i := range 100 {
+			resp.Plugins = append(resp.Plugins, wordpress.PluginInfo{Slug: fmt.Sprintf("plugin-%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+	plugins, err := fetchPlugins(context.Background(), client, wordpress.BrowsePopular, 100, 100, discardLogger())
+	if err != nil {
+		t.Fatalf("fetchPlugins() error = %v", err)
+	}
+	if len(plugins) != 100 {
+		t.Errorf("Expected 100 plugins, got %d", len(plugins))
+	}
+	if len(pages) != 1 || pages[0] != "1/100" {
+		t.Errorf("Expected a single page of 100, requested %v", pages)
+	}
+}
+
+func TestParseFlags_PerPage(t *testing.T) {
+	cfg, err := parseFlags([]string{"-count", "100", "-per-page", "100"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if cfg.Count != 100 || cfg.PerPage != 100 {
+		t.Errorf("Expected count and per-page 100, got %d and %d", cfg.Count, cfg.PerPage)
+	}
+
+	for _, args := range [][]string{{"-per-page", "0"}, {"-per-page", "101"}, {"-count", "0"}} {
+		if _, err := parseFlags(args); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// errVersionUnavailable is reported for pinned versions WordPress.org no
+// longer serves
+var errVersionUnavailable = errors.New("version no longer available")
+
+// errPluginNotFound is reported for pinned slugs WordPress.org doesn't know,
+// e.g. a typo or a closed plugin
+var errPluginNotFound = errors.New("plugin not found")
+
+// ManifestEntry pins a plugin to an exact version
+type ManifestEntry struct {
+	Slug    string
+	Version string
+}
+
+// readManifest reads the manifest file at path
+func readManifest(path string) ([]ManifestEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer f.Close()
+
+	return parseManifest(f)
+}
+
+// parseManifest parses "slug=version" lines. Slugs are normalized with
+// wordpress.NormalizeSlug. Blank lines and lines starting with # are
+// ignored.
+func parseManifest(r io.Reader) ([]ManifestEntry, error) {
+	var entries []ManifestEntry
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		slug, version, ok := strings.Cut(line, "=")
+		slug, version = strings.TrimSpace(slug), strings.TrimSpace(version)
+		if !ok || slug == "" || version == "" {
+			return nil, fmt.Errorf("manifest line %d: expected slug=version, got %q", lineNo, line)
+		}
+		slug, err := wordpress.NormalizeSlug(slug)
+		if err != nil {
+			return nil, fmt.Errorf("manifest line %d: %w", lineNo, err)
+		}
+		entries = append(entries, ManifestEntry{Slug: slug, Version: version})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	return entries, nil
+}
+
+// resolveManifest looks up the download URL of each pinned version. Plugins
+// that don't exist are returned as failed results with errPluginNotFound
+// instead. A version the plugin doesn't list still resolves, to the
+// fallback URL, and is reported with errVersionUnavailable if that download
+// fails.
+func resolveManifest(ctx context.Context, client *wordpress.Client, entries []ManifestEntry, logger *log.Logger) ([]wordpress.PluginInfo, []DownloadResult, error) {
+	var (
+		plugins     []wordpress.PluginInfo
+		unavailable []DownloadResult
+	)
+
+	for _, entry := range entries {
+		downloadURL, err := client.VersionDownloadURL(ctx, entry.Slug, entry.Version)
+		// Only the plugin lookup can be not found
+		if wordpress.IsNotFound(err) {
+			logger.Printf("  ⚠️  %s: %v", entry.Slug, errPluginNotFound)
+			unavailable = append(unavailable, DownloadResult{
+				Slug:    entry.Slug,
+				Version: entry.Version,
+				Error:   errPluginNotFound.Error(),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve %s %s: %w", entry.Slug, entry.Version, err)
+		}
+
+		plugins = append(plugins, wordpress.PluginInfo{
+			Name:         entry.Slug,
+			Slug:         entry.Slug,
+			Version:      entry.Version,
+			DownloadLink: downloadURL,
+		})
+	}
+
+	return plugins, unavailable, nil
+}
+
+// pinnedDownloadError reports a pinned version whose download isn't found
+// with errVersionUnavailable. Other plugins come from a listing of current
+// versions, so a not-found download there isn't relabeled.
+func pinnedDownloadError(err error) error {
+	if wordpress.IsNotFound(err) {
+		return fmt.Errorf("%w: %w", errVersionUnavailable, err)
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/downloader"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []ManifestEntry
+		wantErr bool
+	}{
+		{
+			name:  "entries, comments and blank lines",
+			input: "# production\nakismet=5.3\n\n  hello-dolly = 1.7.2 \n",
+			want: []ManifestEntry{
+				{Slug: "akismet", Version: "5.3"},
+				{Slug: "hello-dolly", Version: "1.7.2"},
+			},
+		},
+		{name: "missing version", input: "akismet=\n", wantErr: true},
+		{name: "missing separator", input: "akismet 5.3\n", wantErr: true},
+		{
+			name:  "slug normalized",
+			input: "Akismet = 5.3\n",
+			want:  []ManifestEntry{{Slug: "akismet", Version: "5.3"}},
+		},
+		{name: "invalid slug", input: "../akismet=5.3\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseManifest(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseManifest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveManifest_Unavailable(t *testing.T) {
+	helloZip := buildPluginZip(t, map[string]string{
+		"hello-dolly/hello.php": "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.6\n*/",
+	})
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/info/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch slug := r.URL.Query().Get("request[slug]"); slug {
+		case "closed":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Plugin not found."}`))
+		default:
+			json.NewEncoder(w).Encode(wordpress.PluginInfo{
+				Slug:     slug,
+				Versions: wordpress.Versions{"1.6": server.URL + "/plugin/" + slug + ".1.6.zip"},
+			})
+		}
+	})
+	mux.HandleFunc("/plugin/hello-dolly.1.6.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(helloZip)
+	})
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL+"/info/"),
+		wordpress.WithDownloadsBaseURL(server.URL+"/plugin/"),
+	)
+	entries := []ManifestEntry{
+		{Slug: "hello-dolly", Version: "1.6"},
+		{Slug: "hello-dolly", Version: "0.9"},
+		{Slug: "closed", Version: "1.0"},
+	}
+
+	plugins, unavailable, err := resolveManifest(context.Background(), client, entries, discardLogger())
+	if err != nil {
+		t.Fatalf("resolveManifest() error = %v", err)
+	}
+	if len(unavailable) != 1 || unavailable[0].Slug != "closed" || unavailable[0].Error != errPluginNotFound.Error() {
+		t.Errorf("Expected closed to be reported as not found, got %+v", unavailable)
+	}
+	if len(plugins) != 2 || plugins[0].DownloadLink != server.URL+"/plugin/hello-dolly.1.6.zip" {
+		t.Fatalf("Unexpected resolved plugins: %+v", plugins)
+	}
+
+	cfg := Config{OutputDir: t.TempDir(), Layout: downloader.LayoutVersioned, Manifest: "manifest.txt"}
+	results := downloadPlugins(context.Background(), client, plugins, cfg, discardLogger())
+
+	if !results[0].Success {
+		t.Errorf("Expected hello-dolly 1.6 to be downloaded, got %+v", results[0])
+	}
+	// 0.9 isn't listed, so the fallback URL is tried and returns 404
+	if results[1].Success || !strings.Contains(results[1].Error, errVersionUnavailable.Error()) {
+		t.Errorf("Expected hello-dolly 0.9 to be reported unavailable, got %+v", results[1])
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// readSlugs reads newline-separated slugs. Blank lines are ignored; the
+// slugs are returned as given and normalized by resolveSlugs.
+func readSlugs(r io.Reader) ([]string, error) {
+	var slugs []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			slugs = append(slugs, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read slugs: %w", err)
+	}
+
+	return slugs, nil
+}
+
+// resolveSlugs normalizes each slug and looks up its latest version.
+// Duplicates are dropped. Slugs that are invalid or can't be looked up are
+// returned as failed results instead, so one bad line doesn't stop the
+// others.
+func resolveSlugs(ctx context.Context, client *wordpress.Client, slugs []string, logger *log.Logger) ([]wordpress.PluginInfo, []DownloadResult, error) {
+	var (
+		plugins []wordpress.PluginInfo
+		failed  []DownloadResult
+	)
+
+	fail := func(slug string, err error) {
+		logger.Printf("  ⚠️  %s: %v", slug, err)
+		failed = append(failed, DownloadResult{Slug: slug, Error: err.Error()})
+	}
+
+	seen := make(map[string]bool)
+	for _, input := range slugs {
+		slug, err := wordpress.NormalizeSlug(input)
+		if err != nil {
+			fail(input, err)
+			continue
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		info, err := client.GetPluginInfo(ctx, slug)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			fail(slug, err)
+			continue
+		}
+		plugins = append(plugins, *info)
+	}
+
+	return plugins, failed, nil
+}
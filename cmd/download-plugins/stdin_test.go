@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/downloader"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestResolveSlugs_FromStdin(t *testing.T) {
+	helloZip := buildPluginZip(t, map[string]string{
+		"hello-dolly/hello.php": "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.7.2\n*/",
+	})
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/info/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch slug := r.URL.Query().Get("request[slug]"); slug {
+		case "hello-dolly":
+			json.NewEncoder(w).Encode(wordpress.PluginInfo{
+				Name:         "Hello Dolly",
+				Slug:         slug,
+				Version:      "1.7.2",
+				DownloadLink: server.URL + "/plugin/hello-dolly.1.7.2.zip",
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Plugin not found."}`))
+		}
+	})
+	mux.HandleFunc("/plugin/hello-dolly.1.7.2.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(helloZip)
+	})
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL + "/info/"))
+
+	stdin := strings.NewReader("hello-dolly\n\n  https://wordpress.org/plugins/hello-dolly/\nclosed\nnot a slug!\n")
+	slugs, err := readSlugs(stdin)
+	if err != nil {
+		t.Fatalf("readSlugs() error = %v", err)
+	}
+	if len(slugs) != 4 {
+		t.Fatalf("Expected 4 slugs, got %q", slugs)
+	}
+
+	plugins, failed, err := resolveSlugs(context.Background(), client, slugs, discardLogger())
+	if err != nil {
+		t.Fatalf("resolveSlugs() error = %v", err)
+	}
+	// The plugin URL normalizes to the slug already listed
+	if len(plugins) != 1 || plugins[0].Slug != "hello-dolly" {
+		t.Fatalf("Expected only hello-dolly to resolve, got %+v", plugins)
+	}
+	if len(failed) != 2 || failed[0].Slug != "closed" || failed[1].Slug != "not a slug!" {
+		t.Fatalf("Expected closed and the invalid slug to fail, got %+v", failed)
+	}
+	for _, r := range failed {
+		if r.Success || r.Error == "" {
+			t.Errorf("Expected %s to be reported as failed, got %+v", r.Slug, r)
+		}
+	}
+
+	cfg := Config{OutputDir: t.TempDir(), Layout: downloader.LayoutSlug}
+	results := downloadPlugins(context.Background(), client, plugins, cfg, discardLogger())
+	if !results[0].Success || results[0].Path != filepath.Join(cfg.OutputDir, "hello-dolly") {
+		t.Errorf("Expected hello-dolly to be downloaded, got %+v", results[0])
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+type Config struct {
+	Path         string
+	Format       string
+	CheckUpdates bool
+	// Deep also reports plugins bundled inside other plugins
+	Deep bool
+}
+
+// Report is the result of scanning a WordPress installation
+type Report struct {
+	CoreVersion string `json:"core_version,omitempty"`
+	// CoreVersionSource is the file the core version was read from
+	CoreVersionSource detector.CoreMarker `json:"core_version_source,omitempty"`
+	Plugins           []Component         `json:"plugins"`
+	Themes            []Component         `json:"themes"`
+}
+
+// Component is a detected plugin or theme
+type Component struct {
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Type     string `json:"type"`
+	Latest   string `json:"latest,omitempty"`
+	Outdated bool   `json:"outdated,omitempty"`
+	// Source is where a plugin is distributed from, set when updates are
+	// checked and the lookup succeeded
+	Source detector.Source `json:"source,omitempty"`
+	// Parent is the slug of the plugin a bundled plugin was found in
+	Parent string `json:"parent,omitempty"`
+	// License is the declared license, as an SPDX identifier when known
+	License string `json:"license,omitempty"`
+}
+
+func main() {
+	cfg := parseFlags()
+
+	if err := run(context.Background(), cfg, wordpress.NewClient(), os.Stdout); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func parseFlags() Config {
+	var cfg Config
+
+	flag.StringVar(&cfg.Path, "path", ".", "Path to the WordPress installation")
+	flag.StringVar(&cfg.Format, "format", formatText, "Output format (text|json)")
+	flag.BoolVar(&cfg.CheckUpdates, "check-updates", false, "Query WordPress.org to flag outdated plugins")
+	flag.BoolVar(&cfg.Deep, "deep", false, "Also report plugins bundled inside other plugins")
+	flag.Parse()
+
+	return cfg
+}
+
+func run(ctx context.Context, cfg Config, client *wordpress.Client, w io.Writer) error {
+	if cfg.Format != formatText && cfg.Format != formatJSON {
+		return fmt.Errorf("unknown format %q", cfg.Format)
+	}
+
+	report, err := scan(os.DirFS(cfg.Path), cfg.Deep)
+	if err != nil {
+		return err
+	}
+
+	if cfg.CheckUpdates {
+		if err := checkUpdates(ctx, client, report); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	return writeText(w, report)
+}
+
+func scan(fsys fs.FS, deep bool) (*Report, error) {
+	report := &Report{
+		Plugins: []Component{},
+		Themes:  []Component{},
+	}
+
+	coreVersion, marker, err := detector.DetectCoreVersion(fsys, ".")
+	if err != nil && !errors.Is(err, detector.ErrCoreVersionNotFound) {
+		return nil, err
+	}
+	report.CoreVersion = coreVersion
+	report.CoreVersionSource = marker
+
+	scanPlugins := detector.ScanPlugins
+	if deep {
+		scanPlugins = func(fsys fs.FS, root string) ([]detector.DetectedPlugin, error) {
+			return detector.ScanPluginsDeep(fsys, root, 0)
+		}
+	}
+	plugins, err := scanPlugins(fsys, "wp-content/plugins")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	muPlugins, err := detector.ScanMuPlugins(fsys, "wp-content")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range append(plugins, muPlugins...) {
+		report.Plugins = append(report.Plugins, Component{
+			Slug:    p.Slug,
+			Name:    p.Name,
+			Version: p.Version,
+			Type:    string(p.Type),
+			Parent:  p.Parent,
+			License: wordpress.NormalizeLicense(p.License),
+		})
+	}
+
+	themes, err := detector.ScanThemes(fsys, "wp-content/themes")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	for _, t := range themes {
+		report.Themes = append(report.Themes, Component{
+			Slug:    t.Slug,
+			Name:    t.Name,
+			Version: t.Version,
+			Type:    "theme",
+		})
+	}
+
+	return report, nil
+}
+
+// checkUpdates audits each regular plugin against WordPress.org and records
+// where it is distributed from and whether it is outdated. Plugins that
+// can't be found (e.g. premium plugins) are reported as external and left
+// unflagged.
+func checkUpdates(ctx context.Context, client *wordpress.Client, report *Report) error {
+	var (
+		plugins []detector.DetectedPlugin
+		indexes []int
+	)
+	for i, p := range report.Plugins {
+		if p.Type == string(detector.TypePlugin) {
+			plugins = append(plugins, detector.DetectedPlugin{
+				PluginHeader: wordpress.PluginHeader{Version: p.Version},
+				Slug:         p.Slug,
+				Type:         detector.TypePlugin,
+			})
+			indexes = append(indexes, i)
+		}
+	}
+
+	results, err := detector.AuditPlugins(ctx, client, plugins)
+	if err != nil {
+		return fmt.Errorf("failed to check for updates: %w", err)
+	}
+
+	// AuditPlugins returns one result per plugin, in order
+	for i, result := range results {
+		if result.Error != nil {
+			log.Printf("Failed to check %s for updates: %v", result.Slug, result.Error)
+			continue
+		}
+		c := &report.Plugins[indexes[i]]
+		c.Source = result.Source
+		c.Latest = result.Latest
+		c.Outdated = result.Outdated
+	}
+
+	return nil
+}
+
+func writeText(w io.Writer, report *Report) error {
+	coreVersion := report.CoreVersion
+	if coreVersion == "" {
+		coreVersion = "unknown"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "WordPress core: %s\n", coreVersion)
+
+	fmt.Fprintf(tw, "\nPlugins (%d):\n", len(report.Plugins))
+	for _, p := range report.Plugins {
+		typ := p.Type
+		if p.Parent != "" {
+			typ += " in " + p.Parent
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", p.Slug, p.Version, typ, p.Source, updateStatus(p))
+	}
+
+	fmt.Fprintf(tw, "\nThemes (%d):\n", len(report.Themes))
+	for _, t := range report.Themes {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", t.Slug, t.Version, t.Type, updateStatus(t))
+	}
+
+	return tw.Flush()
+}
+
+func updateStatus(c Component) string {
+	if c.Outdated {
+		return fmt.Sprintf("outdated (latest %s)", c.Latest)
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// writeTree creates files under root from a map of relative paths to
+// contents
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newSite(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"wp-includes/version.php": "<?php\n$wp_version = '6.4.2';\n",
+		"wp-content/plugins/akismet/akismet.php": `<?php
+/**
+ * Plugin Name: Akismet Anti-spam
+ * Version: 5.0
+ * License: GPLv2 or later
+ */`,
+		"wp-content/plugins/hello.php": `<?php
+/*
+Plugin Name: Hello Dolly
+Version: 1.7.2
+*/`,
+		"wp-content/mu-plugins/loader.php": "<?php\n// loads platform helpers\n",
+		"wp-content/themes/twentytwentyfour/style.css": `/*
+Theme Name: Twenty Twenty-Four
+Version: 1.0
+*/`,
+	})
+
+	return root
+}
+
+func TestRun_JSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug := r.URL.Query().Get("request[slug]")
+		versions := map[string]string{"akismet": "5.5"}
+
+		w.Header().Set("Content-Type", "application/json")
+		version, ok := versions[slug]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Plugin not found."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: slug, Version: version})
+	}))
+	defer server.Close()
+
+	cfg := Config{Path: newSite(t), Format: formatJSON, CheckUpdates: true}
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	var buf bytes.Buffer
+	if err := run(context.Background(), cfg, client, &buf); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to decode output: %v\n%s", err, buf.String())
+	}
+
+	if report.CoreVersion != "6.4.2" {
+		t.Errorf("Expected core version 6.4.2, got %s", report.CoreVersion)
+	}
+
+	plugins := make(map[string]Component)
+	for _, p := range report.Plugins {
+		plugins[p.Slug] = p
+	}
+
+	if len(plugins) != 3 {
+		t.Fatalf("Expected 3 plugins, got %+v", report.Plugins)
+	}
+	if p := plugins["akismet"]; !p.Outdated || p.Latest != "5.5" || p.Source != detector.SourceWordPressOrg {
+		t.Errorf("Expected akismet to be outdated with latest 5.5 on wordpress.org, got %+v", p)
+	}
+	if p := plugins["akismet"]; p.License != "GPL-2.0-or-later" {
+		t.Errorf("Expected akismet license GPL-2.0-or-later, got %q", p.License)
+	}
+	if p := plugins["hello"]; p.Outdated || p.Source != detector.SourceExternal {
+		t.Errorf("Expected hello to be external and not outdated, got %+v", p)
+	}
+	if p := plugins["loader"]; p.Source != "" {
+		t.Errorf("Expected loader not to be checked, got %+v", p)
+	}
+	if p := plugins["loader"]; p.Type != "mu-plugin" {
+		t.Errorf("Expected loader to be a mu-plugin, got %+v", p)
+	}
+
+	if len(report.Themes) != 1 || report.Themes[0].Slug != "twentytwentyfour" || report.Themes[0].Version != "1.0" {
+		t.Errorf("Unexpected themes: %+v", report.Themes)
+	}
+}
+
+func TestRun_Text(t *testing.T) {
+	cfg := Config{Path: newSite(t), Format: formatText}
+
+	var buf bytes.Buffer
+	if err := run(context.Background(), cfg, wordpress.NewClient(), &buf); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"WordPress core: 6.4.2", "akismet", "5.0", "twentytwentyfour"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRun_UnknownFormat(t *testing.T) {
+	cfg := Config{Path: newSite(t), Format: "xml"}
+
+	if err := run(context.Background(), cfg, wordpress.NewClient(), &bytes.Buffer{}); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/masahiro331/go-wp-detector
 
 go 1.24.0
+
+require golang.org/x/time v0.14.0
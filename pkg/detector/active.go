@@ -0,0 +1,48 @@
+package detector
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// serializedString matches a string value in PHP's serialize() format,
+// e.g. s:19:"akismet/akismet.php";
+var serializedString = regexp.MustCompile(`s:\d+:"([^"]*)";`)
+
+// MarkActive sets Active on each plugin whose path appears in
+// activePluginPaths, the plugin basenames stored in the active_plugins
+// option (e.g. "akismet/akismet.php" or "hello.php"). Must-use plugins and
+// dropins are always loaded, so they are marked active regardless.
+func MarkActive(plugins []DetectedPlugin, activePluginPaths []string) {
+	active := make(map[string]bool, len(activePluginPaths))
+	for _, p := range activePluginPaths {
+		active[normalizePluginPath(p)] = true
+	}
+
+	for i := range plugins {
+		if plugins[i].Type != TypePlugin {
+			plugins[i].Active = true
+			continue
+		}
+		plugins[i].Active = active[normalizePluginPath(plugins[i].Path)]
+	}
+}
+
+// ParseActivePlugins extracts the plugin basenames from a dump of the
+// serialized active_plugins option, e.g.
+// a:1:{i:0;s:19:"akismet/akismet.php";}
+func ParseActivePlugins(serialized string) []string {
+	var paths []string
+	for _, m := range serializedString.FindAllStringSubmatch(serialized, -1) {
+		paths = append(paths, m[1])
+	}
+	return paths
+}
+
+// normalizePluginPath cleans a plugin basename so paths written on Windows
+// or with a leading slash still match
+func normalizePluginPath(p string) string {
+	p = strings.ReplaceAll(strings.TrimSpace(p), `\`, "/")
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
@@ -0,0 +1,36 @@
+package detector_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+)
+
+func TestMarkActive(t *testing.T) {
+	plugins := []detector.DetectedPlugin{
+		{Slug: "akismet", Path: "akismet/akismet.php", Type: detector.TypePlugin},
+		{Slug: "akismet", Path: "akismet/uninstall.php", Type: detector.TypePlugin},
+		{Slug: "hello", Path: "hello.php", Type: detector.TypePlugin},
+		{Slug: "woocommerce", Path: "woocommerce/woocommerce.php", Type: detector.TypePlugin},
+		{Slug: "loader", Path: "loader.php", Type: detector.TypeMuPlugin},
+		{Slug: "object-cache", Path: "object-cache.php", Type: detector.TypeDropin},
+	}
+
+	detector.MarkActive(plugins, []string{"akismet/akismet.php", `\hello.php`, "inactive/missing.php"})
+
+	want := []bool{true, false, true, false, true, true}
+	for i, p := range plugins {
+		if p.Active != want[i] {
+			t.Errorf("%s: Active = %v, want %v", p.Path, p.Active, want[i])
+		}
+	}
+}
+
+func TestParseActivePlugins(t *testing.T) {
+	got := detector.ParseActivePlugins(`a:2:{i:0;s:19:"akismet/akismet.php";i:1;s:9:"hello.php";}`)
+	want := []string{"akismet/akismet.php", "hello.php"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseActivePlugins() = %v, want %v", got, want)
+	}
+}
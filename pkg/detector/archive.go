@@ -0,0 +1,102 @@
+package detector
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ScanArchive detects the plugins in a site backup streamed from r, a tar
+// or tar.gz archive, without extracting it. Files are matched the way
+// ScanPlugins matches them under any wp-content/plugins directory in the
+// archive, whatever directory the backup was taken from, and their headers
+// are parsed as the archive is read. Plugins are returned sorted by Path.
+func ScanArchive(r io.Reader) ([]DetectedPlugin, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+
+	var plugins []DetectedPlugin
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		slug, rel, ok := archivePluginPath(hdr.Name)
+		if !ok {
+			continue
+		}
+
+		header, err := wordpress.ReadPluginHeader(tr)
+		if errors.Is(err, wordpress.ErrNoPluginHeader) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+
+		plugins = append(plugins, DetectedPlugin{
+			PluginHeader: *header,
+			Slug:         slug,
+			Path:         rel,
+			Type:         TypePlugin,
+		})
+	}
+
+	slices.SortFunc(plugins, func(a, b DetectedPlugin) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return plugins, nil
+}
+
+// archivePluginPath reports whether name is a PHP file WordPress would check
+// for a plugin header: directly in a wp-content/plugins directory or one
+// directory below it. It returns the plugin slug and the path relative to
+// the plugins directory.
+func archivePluginPath(name string) (slug, rel string, ok bool) {
+	parts := strings.Split(path.Clean(strings.TrimPrefix(name, "./")), "/")
+
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] != "wp-content" || parts[i+1] != "plugins" {
+			continue
+		}
+
+		rest := parts[i+2:]
+		if len(rest) == 0 || len(rest) > 2 || !isPHPFile(rest[len(rest)-1]) {
+			return "", "", false
+		}
+		if len(rest) == 1 {
+			return strings.TrimSuffix(rest[0], ".php"), rest[0], true
+		}
+		return rest[0], path.Join(rest...), true
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,115 @@
+package detector_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+)
+
+func buildTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestScanArchive(t *testing.T) {
+	tarball := buildTar(t, map[string]string{
+		"./public_html/wp-content/plugins/akismet/akismet.php": `<?php
+/**
+ * Plugin Name: Akismet Anti-spam
+ * Version: 5.5
+ */`,
+		"./public_html/wp-content/plugins/akismet/class.akismet.php": "<?php\nclass Akismet {}",
+		"./public_html/wp-content/plugins/akismet/includes/deep.php": `<?php
+/*
+ * Plugin Name: Too Deep
+ */`,
+		"./public_html/wp-content/plugins/hello.php": `<?php
+/*
+Plugin Name: Hello Dolly
+Version: 1.7.2
+*/`,
+		"./public_html/wp-content/themes/twentytwentyfour/functions.php": `<?php
+/*
+ * Plugin Name: Not A Plugin
+ */`,
+		"./public_html/wp-config.php": "<?php\ndefine('DB_NAME', 'wordpress');",
+	})
+
+	want := []struct {
+		slug    string
+		path    string
+		name    string
+		version string
+	}{
+		{"akismet", "akismet/akismet.php", "Akismet Anti-spam", "5.5"},
+		{"hello", "hello.php", "Hello Dolly", "1.7.2"},
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "tar", data: tarball},
+		{name: "tar.gz", data: gzipBytes(t, tarball)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugins, err := detector.ScanArchive(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("ScanArchive() error = %v", err)
+			}
+
+			if len(plugins) != len(want) {
+				t.Fatalf("Expected %d plugins, got %d: %+v", len(want), len(plugins), plugins)
+			}
+			for i, w := range want {
+				p := plugins[i]
+				if p.Slug != w.slug || p.Path != w.path || p.Name != w.name || p.Version != w.version {
+					t.Errorf("plugins[%d] = %+v, want %+v", i, p, w)
+				}
+				if p.Type != detector.TypePlugin {
+					t.Errorf("plugins[%d].Type = %s, want %s", i, p.Type, detector.TypePlugin)
+				}
+			}
+		})
+	}
+}
+
+func TestScanArchive_NotAnArchive(t *testing.T) {
+	if _, err := detector.ScanArchive(bytes.NewReader(gzipBytes(t, []byte("not a tarball, just some text")))); err == nil {
+		t.Error("Expected an error for a gzip stream that isn't a tar archive")
+	}
+}
@@ -0,0 +1,129 @@
+package detector
+
+import (
+	"context"
+	"sync"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// auditConcurrency bounds the number of parallel lookups made by
+// AuditPlugins
+const auditConcurrency = 4
+
+// LatestVersionGetter looks up the latest version of plugins on
+// WordPress.org. It is implemented by *wordpress.Client.
+type LatestVersionGetter interface {
+	LatestVersion(ctx context.Context, slug string) (string, error)
+}
+
+var _ LatestVersionGetter = (*wordpress.Client)(nil)
+
+// Source says where a plugin is distributed from
+type Source string
+
+const (
+	// SourceWordPressOrg is a plugin listed in the WordPress.org directory
+	SourceWordPressOrg Source = "wordpress.org"
+	// SourceExternal is a plugin WordPress.org doesn't know, such as a
+	// premium plugin sold elsewhere or a site-specific one
+	SourceExternal Source = "external"
+)
+
+// AuditResult compares an installed plugin with the latest release on
+// WordPress.org
+type AuditResult struct {
+	Slug      string
+	Installed string
+	Latest    string
+	Outdated  bool
+	// Source is empty when the lookup failed, since it is then unknown
+	// whether WordPress.org lists the plugin
+	Source Source
+	// Error is set when the latest version couldn't be looked up for a
+	// reason other than the plugin being external
+	Error error
+}
+
+// AuditPlugins looks up the latest version of every regular plugin in
+// plugins and reports whether the installed version is outdated. Must-use
+// plugins and dropins are skipped. Lookups run in parallel and each slug is
+// looked up once; a failed lookup is recorded in its result instead of
+// aborting the audit. Plugins WordPress.org doesn't know, as PluginExists
+// decides it, are reported as SourceExternal without an error and are never
+// outdated. An error is only returned if ctx is canceled.
+func AuditPlugins(ctx context.Context, client LatestVersionGetter, plugins []DetectedPlugin) ([]AuditResult, error) {
+	var slugs []string
+	seen := make(map[string]bool)
+	for _, p := range plugins {
+		if p.Type == TypePlugin && !seen[p.Slug] {
+			seen[p.Slug] = true
+			slugs = append(slugs, p.Slug)
+		}
+	}
+
+	type lookup struct {
+		version string
+		err     error
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		lookups = make(map[string]lookup, len(slugs))
+		slugsCh = make(chan string)
+	)
+
+	for range min(auditConcurrency, len(slugs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for slug := range slugsCh {
+				version, err := client.LatestVersion(ctx, slug)
+
+				mu.Lock()
+				lookups[slug] = lookup{version: version, err: err}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, slug := range slugs {
+		if ctx.Err() != nil {
+			break
+		}
+		slugsCh <- slug
+	}
+	close(slugsCh)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var results []AuditResult
+	for _, p := range plugins {
+		if p.Type != TypePlugin {
+			continue
+		}
+
+		l := lookups[p.Slug]
+		result := AuditResult{
+			Slug:      p.Slug,
+			Installed: p.Version,
+		}
+		switch {
+		case l.err == nil:
+			result.Source = SourceWordPressOrg
+			result.Latest = l.version
+			result.Outdated = wordpress.IsOutdated(p.Version, l.version)
+		case wordpress.IsNotFound(l.err):
+			result.Source = SourceExternal
+		default:
+			result.Error = l.err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,109 @@
+package detector_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// fakeClient returns canned plugin versions and records lookups
+type fakeClient struct {
+	mu       sync.Mutex
+	versions map[string]string
+	calls    map[string]int
+}
+
+func (c *fakeClient) LatestVersion(ctx context.Context, slug string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+	}
+	c.calls[slug]++
+
+	version, ok := c.versions[slug]
+	if !ok {
+		return "", &wordpress.APIError{StatusCode: 404, Message: "Plugin not found."}
+	}
+	return version, nil
+}
+
+func TestAuditPlugins(t *testing.T) {
+	client := &fakeClient{versions: map[string]string{
+		"akismet":        "5.5",
+		"hello-dolly":    "1.7.2",
+		"contact-form-7": "6.1.3",
+	}}
+
+	plugins := []detector.DetectedPlugin{
+		{PluginHeader: wordpress.PluginHeader{Version: "5.3"}, Slug: "akismet", Path: "akismet/akismet.php", Type: detector.TypePlugin},
+		{PluginHeader: wordpress.PluginHeader{Version: "1.7.2"}, Slug: "hello-dolly", Path: "hello-dolly/hello.php", Type: detector.TypePlugin},
+		{PluginHeader: wordpress.PluginHeader{Version: "6.1.10"}, Slug: "contact-form-7", Path: "contact-form-7/wp-contact-form-7.php", Type: detector.TypePlugin},
+		{PluginHeader: wordpress.PluginHeader{Version: "1.0"}, Slug: "premium", Path: "premium/premium.php", Type: detector.TypePlugin},
+		{PluginHeader: wordpress.PluginHeader{Version: "6.2.0"}, Slug: "acf-pro", Path: "acf-pro/acf.php", Type: detector.TypePlugin},
+		{PluginHeader: wordpress.PluginHeader{Version: "0.3"}, Slug: "loader", Path: "loader.php", Type: detector.TypeMuPlugin},
+	}
+
+	results, err := detector.AuditPlugins(context.Background(), client, plugins)
+	if err != nil {
+		t.Fatalf("AuditPlugins() error = %v", err)
+	}
+
+	want := []detector.AuditResult{
+		{Slug: "akismet", Installed: "5.3", Latest: "5.5", Outdated: true, Source: detector.SourceWordPressOrg},
+		{Slug: "hello-dolly", Installed: "1.7.2", Latest: "1.7.2", Source: detector.SourceWordPressOrg},
+		{Slug: "contact-form-7", Installed: "6.1.10", Latest: "6.1.3", Source: detector.SourceWordPressOrg},
+		{Slug: "premium", Installed: "1.0", Source: detector.SourceExternal},
+		{Slug: "acf-pro", Installed: "6.2.0", Source: detector.SourceExternal},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d results, got %d: %+v", len(want), len(results), results)
+	}
+	for i, w := range want {
+		got := results[i]
+		if got.Slug != w.Slug || got.Installed != w.Installed || got.Latest != w.Latest || got.Outdated != w.Outdated || got.Source != w.Source || got.Error != nil {
+			t.Errorf("results[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+
+	if _, ok := client.calls["loader"]; ok {
+		t.Error("Expected must-use plugins not to be looked up")
+	}
+}
+
+func TestAuditPlugins_LookupError(t *testing.T) {
+	client := &failingClient{err: &wordpress.APIError{StatusCode: 500, Message: "Internal Server Error"}}
+	plugins := []detector.DetectedPlugin{{Slug: "akismet", Type: detector.TypePlugin}}
+
+	results, err := detector.AuditPlugins(context.Background(), client, plugins)
+	if err != nil {
+		t.Fatalf("AuditPlugins() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Error == nil || results[0].Source != "" {
+		t.Errorf("Expected a failed lookup with an unknown source, got %+v", results)
+	}
+}
+
+// failingClient fails every lookup with err
+type failingClient struct {
+	err error
+}
+
+func (c *failingClient) LatestVersion(ctx context.Context, slug string) (string, error) {
+	return "", c.err
+}
+
+func TestAuditPlugins_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	plugins := []detector.DetectedPlugin{{Slug: "akismet", Type: detector.TypePlugin}}
+	if _, err := detector.AuditPlugins(ctx, &fakeClient{}, plugins); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
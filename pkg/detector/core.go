@@ -0,0 +1,59 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+)
+
+// ErrCoreVersionNotFound is returned when the WordPress core version cannot
+// be determined
+var ErrCoreVersionNotFound = errors.New("wordpress core version not found")
+
+// CoreMarker names the file the core version was read from
+type CoreMarker string
+
+// Core version markers, in the order DetectCoreVersion tries them
+const (
+	MarkerVersionPHP      CoreMarker = "wp-includes/version.php"
+	MarkerBlockLibraryCSS CoreMarker = "wp-includes/css/dist/block-library/style.css"
+	MarkerReadme          CoreMarker = "readme.html"
+)
+
+// coreMarkers pairs each marker with the pattern capturing the version in it
+var coreMarkers = []struct {
+	marker  CoreMarker
+	pattern *regexp.Regexp
+}{
+	// The $wp_version assignment
+	{MarkerVersionPHP, regexp.MustCompile(`\$wp_version\s*=\s*['"]([^'"]+)['"]`)},
+	// A "Version: X.Y" line in a comment
+	{MarkerBlockLibraryCSS, regexp.MustCompile(`/\*(?:[^*]|\*[^/])*?\bVersion:?\s+(\d+\.\d+(?:\.\d+)?)`)},
+	// "<br /> Version X.Y" under the logo
+	{MarkerReadme, regexp.MustCompile(`(?i)<br\s*/?>\s*Version\s+(\d+\.\d+(?:\.\d+)?)`)},
+}
+
+// DetectCoreVersion returns the WordPress core version of the installation at
+// root and the marker it was read from. version.php is the authoritative
+// source, but can be missing or modified on stripped installs, so the
+// version comment of the block library stylesheet and the version in
+// readme.html are tried next.
+func DetectCoreVersion(fsys fs.FS, root string) (string, CoreMarker, error) {
+	for _, m := range coreMarkers {
+		content, err := fs.ReadFile(fsys, path.Join(root, string(m.marker)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read %s: %w", m.marker, err)
+		}
+
+		if match := m.pattern.FindSubmatch(content); match != nil {
+			return string(match[1]), m.marker, nil
+		}
+	}
+
+	return "", "", ErrCoreVersionNotFound
+}
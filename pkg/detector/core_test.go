@@ -0,0 +1,103 @@
+package detector_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+)
+
+func TestDetectCoreVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		fsys       fstest.MapFS
+		want       string
+		wantMarker detector.CoreMarker
+		wantErr    error
+	}{
+		{
+			name: "version.php present",
+			fsys: fstest.MapFS{
+				"wp-includes/version.php": {Data: []byte(`<?php
+/**
+ * WordPress Version
+ *
+ * Contains version information for the current WordPress release.
+ */
+
+/**
+ * The WordPress version string.
+ *
+ * @global string $wp_version
+ */
+$wp_version = '6.4.2';
+
+$wp_db_version = 56657;`)},
+				"readme.html": {Data: []byte(`<h1 id="logo"><img alt="WordPress" src="wp-admin/images/wordpress-logo.png" /><br /> Version 6.4</h1>`)},
+			},
+			want:       "6.4.2",
+			wantMarker: detector.MarkerVersionPHP,
+		},
+		{
+			name:    "version.php missing",
+			fsys:    fstest.MapFS{},
+			wantErr: detector.ErrCoreVersionNotFound,
+		},
+		{
+			name: "version.php without version",
+			fsys: fstest.MapFS{
+				"wp-includes/version.php": {Data: []byte(`<?php`)},
+			},
+			wantErr: detector.ErrCoreVersionNotFound,
+		},
+		{
+			name: "version.php missing, readme.html present",
+			fsys: fstest.MapFS{
+				"readme.html": {Data: []byte(`<!DOCTYPE html>
+<html lang="en">
+<body>
+<h1 id="logo">
+	<a href="https://wordpress.org/"><img alt="WordPress" src="wp-admin/images/wordpress-logo.png" /></a>
+	<br /> Version 6.2.3
+</h1>
+<p style="text-align: center">Semantic Personal Publishing Platform</p>`)},
+			},
+			want:       "6.2.3",
+			wantMarker: detector.MarkerReadme,
+		},
+		{
+			name: "version.php modified, block library stylesheet present",
+			fsys: fstest.MapFS{
+				"wp-includes/version.php":                      {Data: []byte(`<?php // removed`)},
+				"wp-includes/css/dist/block-library/style.css": {Data: []byte("/*\n * Block library styles\n * Version: 6.3.1\n */\n.wp-block-audio{box-sizing:border-box}")},
+				"readme.html":                                  {Data: []byte(`<br /> Version 6.3`)},
+			},
+			want:       "6.3.1",
+			wantMarker: detector.MarkerBlockLibraryCSS,
+		},
+		{
+			name: "readme.html without version",
+			fsys: fstest.MapFS{
+				"readme.html": {Data: []byte(`<h1 id="logo"><img alt="WordPress" src="wp-admin/images/wordpress-logo.png" /></h1>`)},
+			},
+			wantErr: detector.ErrCoreVersionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, marker, err := detector.DetectCoreVersion(tt.fsys, ".")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DetectCoreVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DetectCoreVersion() = %s, want %s", got, tt.want)
+			}
+			if marker != tt.wantMarker {
+				t.Errorf("DetectCoreVersion() marker = %s, want %s", marker, tt.wantMarker)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package detector
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// DefaultDeepScanDepth is how many directories below a plugin directory
+// ScanPluginsDeep looks for bundled plugins by default
+const DefaultDeepScanDepth = 4
+
+// ScanPluginsDeep is like ScanPlugins but also finds plugins bundled inside
+// other plugins, e.g. add-ons a page builder ships in a subdirectory, which
+// WordPress doesn't list but which still run when the parent loads them.
+// Each plugin directory is searched up to maxDepth directories deep (0
+// means DefaultDeepScanDepth); bundled plugins have Parent set to the slug
+// of the top-level plugin and Path relative to root. Symbolic links are not
+// followed, so the search always ends.
+func ScanPluginsDeep(fsys fs.FS, root string, maxDepth int) ([]DetectedPlugin, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultDeepScanDepth
+	}
+
+	plugins, err := ScanPlugins(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		nested, err := scanNestedPlugins(fsys, root, entry.Name(), entry.Name(), maxDepth)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, nested...)
+	}
+
+	return plugins, nil
+}
+
+// scanNestedPlugins looks for plugin headers in the subdirectories of dir,
+// which is relative to root, descending at most depth levels
+func scanNestedPlugins(fsys fs.FS, root, dir, parent string, depth int) ([]DetectedPlugin, error) {
+	if depth == 0 {
+		return nil, nil
+	}
+
+	entries, err := fs.ReadDir(fsys, path.Join(root, dir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory %s: %w", dir, err)
+	}
+
+	var plugins []DetectedPlugin
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		sub := path.Join(dir, entry.Name())
+
+		found, err := scanPluginDir(fsys, root, sub)
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			found[i].Slug = entry.Name()
+			found[i].Parent = parent
+		}
+		plugins = append(plugins, found...)
+
+		nested, err := scanNestedPlugins(fsys, root, sub, parent, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, nested...)
+	}
+
+	return plugins, nil
+}
@@ -0,0 +1,21 @@
+package detector
+
+import "github.com/masahiro331/go-wp-detector/pkg/wordpress"
+
+// CheckDependencies returns the slugs p requires, per its "requires_plugins"
+// field, that aren't among the installed plugins. WordPress refuses to
+// activate a plugin until all of them are installed and active.
+func CheckDependencies(installed []DetectedPlugin, p wordpress.PluginInfo) []string {
+	have := make(map[string]bool, len(installed))
+	for _, plugin := range installed {
+		have[plugin.Slug] = true
+	}
+
+	var missing []string
+	for _, slug := range p.RequiresPlugins {
+		if !have[slug] {
+			missing = append(missing, slug)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,35 @@
+package detector_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	installed := []detector.DetectedPlugin{
+		{Slug: "woocommerce", Path: "woocommerce/woocommerce.php", Type: detector.TypePlugin},
+		{Slug: "akismet", Path: "akismet/akismet.php", Type: detector.TypePlugin},
+	}
+
+	tests := []struct {
+		name     string
+		requires wordpress.PluginSlugs
+		want     []string
+	}{
+		{name: "satisfied", requires: wordpress.PluginSlugs{"woocommerce"}},
+		{name: "missing", requires: wordpress.PluginSlugs{"jetpack", "woocommerce", "woocommerce-payments"}, want: []string{"jetpack", "woocommerce-payments"}},
+		{name: "no dependencies"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detector.CheckDependencies(installed, wordpress.PluginInfo{Slug: "extension", RequiresPlugins: tt.requires})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("CheckDependencies() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
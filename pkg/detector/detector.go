@@ -0,0 +1,229 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// PluginType distinguishes the ways WordPress loads plugin code
+type PluginType string
+
+const (
+	// TypePlugin is a regular plugin under wp-content/plugins
+	TypePlugin PluginType = "plugin"
+	// TypeMuPlugin is a must-use plugin under wp-content/mu-plugins
+	TypeMuPlugin PluginType = "mu-plugin"
+	// TypeDropin is a dropin file placed directly in wp-content
+	TypeDropin PluginType = "dropin"
+)
+
+// Dropins lists the dropin filenames WordPress loads from wp-content
+// (see _get_dropins in wp-admin/includes/plugin.php)
+var Dropins = []string{
+	"advanced-cache.php",
+	"db.php",
+	"db-error.php",
+	"install.php",
+	"maintenance.php",
+	"object-cache.php",
+	"php-error.php",
+	"fatal-error-handler.php",
+	"sunrise.php",
+	"blog-deleted.php",
+	"blog-inactive.php",
+	"blog-suspended.php",
+}
+
+// DetectedPlugin is a plugin found on disk together with its parsed header
+type DetectedPlugin struct {
+	wordpress.PluginHeader
+
+	// Slug is the plugin directory name, or the file name without the .php
+	// extension for single-file plugins
+	Slug string
+	// Path is the plugin file path relative to the directory it is loaded
+	// from (e.g. "akismet/akismet.php" for plugins, "loader.php" for
+	// must-use plugins)
+	Path string
+	Type PluginType
+	// Active is set by MarkActive; it is false until then
+	Active bool
+	// Parent is the slug of the plugin this one is bundled in, set only by
+	// ScanPluginsDeep
+	Parent string
+}
+
+// ScanPlugins scans a plugins directory (usually wp-content/plugins) the way
+// get_plugins does: PHP files directly in root and PHP files one directory
+// below it are checked for a "Plugin Name" header.
+func ScanPlugins(fsys fs.FS, root string) ([]DetectedPlugin, error) {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	var plugins []DetectedPlugin
+	for _, entry := range entries {
+		if entry.IsDir() {
+			found, err := scanPluginDir(fsys, root, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			plugins = append(plugins, found...)
+			continue
+		}
+
+		if !isPHPFile(entry.Name()) {
+			continue
+		}
+
+		header, err := readPluginHeader(fsys, path.Join(root, entry.Name()))
+		if errors.Is(err, wordpress.ErrNoPluginHeader) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		plugins = append(plugins, DetectedPlugin{
+			PluginHeader: *header,
+			Slug:         strings.TrimSuffix(entry.Name(), ".php"),
+			Path:         entry.Name(),
+			Type:         TypePlugin,
+		})
+	}
+
+	return plugins, nil
+}
+
+func scanPluginDir(fsys fs.FS, root, dir string) ([]DetectedPlugin, error) {
+	entries, err := fs.ReadDir(fsys, path.Join(root, dir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory %s: %w", dir, err)
+	}
+
+	var plugins []DetectedPlugin
+	for _, entry := range entries {
+		if entry.IsDir() || !isPHPFile(entry.Name()) {
+			continue
+		}
+
+		header, err := readPluginHeader(fsys, path.Join(root, dir, entry.Name()))
+		if errors.Is(err, wordpress.ErrNoPluginHeader) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		plugins = append(plugins, DetectedPlugin{
+			PluginHeader: *header,
+			Slug:         dir,
+			Path:         path.Join(dir, entry.Name()),
+			Type:         TypePlugin,
+		})
+	}
+
+	return plugins, nil
+}
+
+// ScanMuPlugins scans a wp-content directory for must-use plugins and
+// dropins. Every top-level PHP file in root/mu-plugins is loaded by
+// WordPress unconditionally, so each one is reported even when its header is
+// incomplete; a missing "Plugin Name" falls back to the file name, as
+// get_mu_plugins does. Known dropin files in root are reported the same way.
+func ScanMuPlugins(fsys fs.FS, root string) ([]DetectedPlugin, error) {
+	var plugins []DetectedPlugin
+
+	muDir := path.Join(root, "mu-plugins")
+	entries, err := fs.ReadDir(fsys, muDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read mu-plugins directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isPHPFile(entry.Name()) {
+			continue
+		}
+
+		p, err := detectFilePlugin(fsys, muDir, entry.Name(), TypeMuPlugin)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, p)
+	}
+
+	for _, name := range Dropins {
+		info, err := fs.Stat(fsys, path.Join(root, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat dropin %s: %w", name, err)
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		p, err := detectFilePlugin(fsys, root, name, TypeDropin)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, p)
+	}
+
+	return plugins, nil
+}
+
+// detectFilePlugin parses a single-file plugin that does not need a
+// "Plugin Name" header to be loaded
+func detectFilePlugin(fsys fs.FS, dir, name string, typ PluginType) (DetectedPlugin, error) {
+	header, err := readFileHeader(fsys, path.Join(dir, name))
+	if err != nil {
+		return DetectedPlugin{}, err
+	}
+	if header.Name == "" {
+		header.Name = name
+	}
+
+	return DetectedPlugin{
+		PluginHeader: header,
+		Slug:         strings.TrimSuffix(name, ".php"),
+		Path:         name,
+		Type:         typ,
+	}, nil
+}
+
+func readPluginHeader(fsys fs.FS, name string) (*wordpress.PluginHeader, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	return wordpress.ReadPluginHeader(f)
+}
+
+func readFileHeader(fsys fs.FS, name string) (wordpress.PluginHeader, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return wordpress.PluginHeader{}, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	return wordpress.ReadFileHeader(f)
+}
+
+func isPHPFile(name string) bool {
+	return strings.HasSuffix(name, ".php")
+}
+
+// PURL returns the package URL of the plugin, pkg:wordpress/<slug>@<version>
+func (p DetectedPlugin) PURL() string {
+	return wordpress.FormatPURL(p.Slug, p.Version)
+}
@@ -0,0 +1,194 @@
+package detector_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+)
+
+func TestScanPlugins(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wp-content/plugins/akismet/akismet.php": {Data: []byte(`<?php
+/**
+ * Plugin Name: Akismet Anti-spam
+ * Version: 5.5
+ */`)},
+		"wp-content/plugins/akismet/class.akismet.php": {Data: []byte(`<?php
+class Akismet {}`)},
+		"wp-content/plugins/akismet/includes/deep.php": {Data: []byte(`<?php
+/*
+ * Plugin Name: Too Deep
+ */`)},
+		"wp-content/plugins/hello.php": {Data: []byte(`<?php
+/*
+Plugin Name: Hello Dolly
+Version: 1.7.2
+*/`)},
+		"wp-content/plugins/index.php": {Data: []byte(`<?php
+// Silence is golden.`)},
+	}
+
+	plugins, err := detector.ScanPlugins(fsys, "wp-content/plugins")
+	if err != nil {
+		t.Fatalf("ScanPlugins() error = %v", err)
+	}
+
+	want := []struct {
+		slug    string
+		path    string
+		name    string
+		version string
+	}{
+		{"akismet", "akismet/akismet.php", "Akismet Anti-spam", "5.5"},
+		{"hello", "hello.php", "Hello Dolly", "1.7.2"},
+	}
+
+	if len(plugins) != len(want) {
+		t.Fatalf("Expected %d plugins, got %d: %+v", len(want), len(plugins), plugins)
+	}
+
+	for i, w := range want {
+		p := plugins[i]
+		if p.Slug != w.slug || p.Path != w.path || p.Name != w.name || p.Version != w.version {
+			t.Errorf("plugins[%d] = %+v, want %+v", i, p, w)
+		}
+		if p.Type != detector.TypePlugin {
+			t.Errorf("plugins[%d].Type = %s, want %s", i, p.Type, detector.TypePlugin)
+		}
+	}
+}
+
+func TestScanMuPlugins(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wp-content/mu-plugins/loader.php": {Data: []byte(`<?php
+/**
+ * Description: Loads the platform helpers
+ * Version: 0.3
+ */
+require __DIR__ . '/helpers/helpers.php';`)},
+		"wp-content/mu-plugins/helpers/helpers.php": {Data: []byte(`<?php
+/*
+ * Plugin Name: Nested Helper
+ */`)},
+		"wp-content/mu-plugins/readme.txt": {Data: []byte("not php")},
+		"wp-content/object-cache.php": {Data: []byte(`<?php
+/*
+Plugin Name: Redis Object Cache Drop-In
+Version: 2.5.0
+*/`)},
+		"wp-content/unrelated.php": {Data: []byte(`<?php`)},
+	}
+
+	plugins, err := detector.ScanMuPlugins(fsys, "wp-content")
+	if err != nil {
+		t.Fatalf("ScanMuPlugins() error = %v", err)
+	}
+
+	if len(plugins) != 2 {
+		t.Fatalf("Expected 2 plugins, got %d: %+v", len(plugins), plugins)
+	}
+
+	mu := plugins[0]
+	if mu.Type != detector.TypeMuPlugin {
+		t.Errorf("Expected type %s, got %s", detector.TypeMuPlugin, mu.Type)
+	}
+	if mu.Name != "loader.php" {
+		t.Errorf("Expected name to fall back to file name, got %s", mu.Name)
+	}
+	if mu.Version != "0.3" {
+		t.Errorf("Expected version 0.3, got %s", mu.Version)
+	}
+	if mu.Description != "Loads the platform helpers" {
+		t.Errorf("Expected description to be parsed, got %s", mu.Description)
+	}
+	if mu.Slug != "loader" {
+		t.Errorf("Expected slug loader, got %s", mu.Slug)
+	}
+
+	dropin := plugins[1]
+	if dropin.Type != detector.TypeDropin {
+		t.Errorf("Expected type %s, got %s", detector.TypeDropin, dropin.Type)
+	}
+	if dropin.Path != "object-cache.php" {
+		t.Errorf("Expected path object-cache.php, got %s", dropin.Path)
+	}
+	if dropin.Name != "Redis Object Cache Drop-In" || dropin.Version != "2.5.0" {
+		t.Errorf("Unexpected dropin header: %+v", dropin.PluginHeader)
+	}
+}
+
+func TestScanMuPlugins_NoMuPluginsDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wp-content/plugins/hello.php": {Data: []byte(`<?php`)},
+	}
+
+	plugins, err := detector.ScanMuPlugins(fsys, "wp-content")
+	if err != nil {
+		t.Fatalf("ScanMuPlugins() error = %v", err)
+	}
+	if len(plugins) != 0 {
+		t.Errorf("Expected no plugins, got %+v", plugins)
+	}
+}
+
+func TestScanPluginsDeep(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wp-content/plugins/builder/builder.php": {Data: []byte(`<?php
+/*
+ * Plugin Name: Page Builder
+ * Version: 3.0
+ */`)},
+		"wp-content/plugins/builder/vendor/woocommerce/woocommerce.php": {Data: []byte(`<?php
+/**
+ * Plugin Name: WooCommerce
+ * Version: 8.5.1
+ */`)},
+		"wp-content/plugins/builder/vendor/woocommerce/includes/class-wc.php": {Data: []byte(`<?php
+class WooCommerce {}`)},
+		"wp-content/plugins/builder/a/b/c/d/e/buried.php": {Data: []byte(`<?php
+/*
+ * Plugin Name: Buried
+ */`)},
+		"wp-content/plugins/hello.php": {Data: []byte(`<?php
+/*
+Plugin Name: Hello Dolly
+Version: 1.7.2
+*/`)},
+	}
+
+	plugins, err := detector.ScanPluginsDeep(fsys, "wp-content/plugins", 0)
+	if err != nil {
+		t.Fatalf("ScanPluginsDeep() error = %v", err)
+	}
+
+	want := []struct {
+		slug    string
+		path    string
+		version string
+		parent  string
+	}{
+		{"builder", "builder/builder.php", "3.0", ""},
+		{"hello", "hello.php", "1.7.2", ""},
+		{"woocommerce", "builder/vendor/woocommerce/woocommerce.php", "8.5.1", "builder"},
+	}
+
+	// buried.php is five directories below the plugin, beyond the default depth
+	if len(plugins) != len(want) {
+		t.Fatalf("Expected %d plugins, got %d: %+v", len(want), len(plugins), plugins)
+	}
+	for i, w := range want {
+		p := plugins[i]
+		if p.Slug != w.slug || p.Path != w.path || p.Version != w.version || p.Parent != w.parent {
+			t.Errorf("plugins[%d] = %+v, want %+v", i, p, w)
+		}
+	}
+
+	plugins, err = detector.ScanPluginsDeep(fsys, "wp-content/plugins", 5)
+	if err != nil {
+		t.Fatalf("ScanPluginsDeep() error = %v", err)
+	}
+	if len(plugins) != len(want)+1 || plugins[2].Path != "builder/a/b/c/d/e/buried.php" {
+		t.Errorf("Expected the buried plugin with a depth of 5, got %+v", plugins)
+	}
+}
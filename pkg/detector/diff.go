@@ -0,0 +1,112 @@
+package detector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ChangeKind describes how a file differs between two plugin versions
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// FileChange is a file that differs between two plugin versions. Hashes are
+// hex-encoded SHA-256 digests; OldHash is empty for added files and NewHash
+// for removed ones.
+type FileChange struct {
+	Path    string
+	Kind    ChangeKind
+	OldHash string
+	NewHash string
+}
+
+// DiffVersions compares two extracted plugin directories, e.g. two versions
+// side by side in the versioned download layout, and returns the files that
+// were added, removed or modified in dirB relative to dirA, sorted by path.
+// Files are compared by content hash and read one at a time, so large
+// plugins are never held in memory.
+func DiffVersions(dirA, dirB string) ([]FileChange, error) {
+	oldHashes, err := hashTree(os.DirFS(dirA))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dirA, err)
+	}
+	newHashes, err := hashTree(os.DirFS(dirB))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dirB, err)
+	}
+
+	return diffHashes(oldHashes, newHashes), nil
+}
+
+// diffHashes compares two path to hash maps and returns the changes from
+// oldHashes to newHashes sorted by path
+func diffHashes(oldHashes, newHashes map[string]string) []FileChange {
+	var changes []FileChange
+	for name, oldHash := range oldHashes {
+		newHash, ok := newHashes[name]
+		switch {
+		case !ok:
+			changes = append(changes, FileChange{Path: name, Kind: ChangeRemoved, OldHash: oldHash})
+		case newHash != oldHash:
+			changes = append(changes, FileChange{Path: name, Kind: ChangeModified, OldHash: oldHash, NewHash: newHash})
+		}
+	}
+	for name, newHash := range newHashes {
+		if _, ok := oldHashes[name]; !ok {
+			changes = append(changes, FileChange{Path: name, Kind: ChangeAdded, NewHash: newHash})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b FileChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return changes
+}
+
+// hashTree returns the SHA-256 digest of every regular file in fsys keyed
+// by path
+func hashTree(fsys fs.FS) (map[string]string, error) {
+	hashes := make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		sum, err := hashFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		hashes[name] = sum
+		return nil
+	})
+
+	return hashes, err
+}
+
+func hashFile(fsys fs.FS, name string) (string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", name, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
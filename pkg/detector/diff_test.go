@@ -0,0 +1,72 @@
+package detector_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiffVersions(t *testing.T) {
+	dirA := t.TempDir()
+	dirB := t.TempDir()
+
+	writeFiles(t, dirA, map[string]string{
+		"hello.php":            "<?php // 1.7.1",
+		"readme.txt":           "=== Hello Dolly ===",
+		"includes/lyrics.php":  "<?php // lyrics",
+		"includes/removed.php": "<?php // old",
+	})
+	writeFiles(t, dirB, map[string]string{
+		"hello.php":           "<?php // 1.7.2",
+		"readme.txt":          "=== Hello Dolly ===",
+		"includes/lyrics.php": "<?php // lyrics",
+		"includes/added.php":  "<?php // new",
+	})
+
+	changes, err := detector.DiffVersions(dirA, dirB)
+	if err != nil {
+		t.Fatalf("DiffVersions() error = %v", err)
+	}
+
+	want := []struct {
+		path string
+		kind detector.ChangeKind
+	}{
+		{"hello.php", detector.ChangeModified},
+		{"includes/added.php", detector.ChangeAdded},
+		{"includes/removed.php", detector.ChangeRemoved},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected %d changes, got %+v", len(want), changes)
+	}
+	for i, w := range want {
+		c := changes[i]
+		if c.Path != w.path || c.Kind != w.kind {
+			t.Errorf("changes[%d] = %+v, want %s %s", i, c, w.kind, w.path)
+		}
+	}
+
+	modified := changes[0]
+	if modified.OldHash == "" || modified.NewHash == "" || modified.OldHash == modified.NewHash {
+		t.Errorf("Expected differing hashes for a modified file, got %+v", modified)
+	}
+	if changes[1].OldHash != "" || changes[2].NewHash != "" {
+		t.Errorf("Expected only one hash for added and removed files, got %+v", changes[1:])
+	}
+}
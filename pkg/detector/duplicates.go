@@ -0,0 +1,81 @@
+package detector
+
+import "strings"
+
+// FindDuplicates returns groups of plugins that look like copies of the same
+// plugin installed under different directory or file names: they declare the
+// same Text Domain, or the same Plugin Name and Version. Copies are easy to
+// forget when updating and may shadow each other's functions. Groups sharing
+// a plugin are merged, only groups spanning at least two slugs are returned,
+// and both the groups and their members keep the order of plugins.
+func FindDuplicates(plugins []DetectedPlugin) [][]DetectedPlugin {
+	// Union-find over plugin indexes, joined through the first plugin seen
+	// with each key
+	parent := make([]int, len(plugins))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	firstByKey := make(map[string]int)
+	for i, p := range plugins {
+		for _, key := range duplicateKeys(p) {
+			first, ok := firstByKey[key]
+			if !ok {
+				firstByKey[key] = i
+				continue
+			}
+			if a, b := find(first), find(i); a != b {
+				parent[max(a, b)] = min(a, b)
+			}
+		}
+	}
+
+	groups := make(map[int][]DetectedPlugin)
+	var roots []int
+	for i, p := range plugins {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], p)
+	}
+
+	var duplicates [][]DetectedPlugin
+	for _, root := range roots {
+		if group := groups[root]; spansSlugs(group) {
+			duplicates = append(duplicates, group)
+		}
+	}
+	return duplicates
+}
+
+// duplicateKeys returns the identities under which p is compared with other
+// plugins
+func duplicateKeys(p DetectedPlugin) []string {
+	var keys []string
+	if td := strings.ToLower(strings.TrimSpace(p.TextDomain)); td != "" {
+		keys = append(keys, "text-domain:"+td)
+	}
+	if p.Name != "" {
+		keys = append(keys, "name:"+p.Name+"\x00"+p.Version)
+	}
+	return keys
+}
+
+// spansSlugs reports whether group holds plugins from more than one slug;
+// several plugin files in one directory aren't copies of each other
+func spansSlugs(group []DetectedPlugin) bool {
+	for _, p := range group[1:] {
+		if p.Slug != group[0].Slug {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package detector_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"akismet/akismet.php": `<?php
+/*
+ * Plugin Name: Akismet Anti-spam
+ * Version: 5.3
+ * Text Domain: akismet
+ */`,
+		"akismet-old/akismet.php": `<?php
+/*
+ * Plugin Name: Akismet Anti-spam
+ * Version: 5.1
+ * Text Domain: akismet
+ */`,
+		"hello-dolly/hello.php": `<?php
+/*
+Plugin Name: Hello Dolly
+Version: 1.7.2
+*/`,
+		"hello-dolly-copy/hello.php": `<?php
+/*
+Plugin Name: Hello Dolly
+Version: 1.7.2
+*/`,
+		"hello-dolly-new/hello.php": `<?php
+/*
+Plugin Name: Hello Dolly
+Version: 1.7.3
+*/`,
+		"woocommerce/woocommerce.php": `<?php
+/*
+ * Plugin Name: WooCommerce
+ * Text Domain: woocommerce
+ */`,
+		"woocommerce/legacy.php": `<?php
+/*
+ * Plugin Name: WooCommerce Legacy
+ * Text Domain: woocommerce
+ */`,
+	})
+
+	plugins, err := detector.ScanPlugins(os.DirFS(root), ".")
+	if err != nil {
+		t.Fatalf("ScanPlugins() error = %v", err)
+	}
+
+	groups := detector.FindDuplicates(plugins)
+
+	want := [][]string{
+		{"akismet", "akismet-old"},
+		{"hello-dolly", "hello-dolly-copy"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("Expected %d groups, got %+v", len(want), groups)
+	}
+	for i, slugs := range want {
+		if len(groups[i]) != len(slugs) {
+			t.Errorf("groups[%d] = %+v, want slugs %v", i, groups[i], slugs)
+			continue
+		}
+		for j, slug := range slugs {
+			if groups[i][j].Slug != slug {
+				t.Errorf("groups[%d][%d].Slug = %s, want %s", i, j, groups[i][j].Slug, slug)
+			}
+		}
+	}
+}
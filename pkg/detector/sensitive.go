@@ -0,0 +1,115 @@
+package detector
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Severity rates how much a finding exposes
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// SensitivePattern matches the base names of files or directories that
+// shouldn't be left on a public site
+type SensitivePattern struct {
+	// Pattern is a path.Match pattern compared with the lowercased base name
+	Pattern  string
+	Severity Severity
+}
+
+// defaultSensitivePatterns is the set ScanSensitiveFiles looks for unless
+// given its own
+var defaultSensitivePatterns = []SensitivePattern{
+	// Editor and manual backups of the configuration expose the database
+	// credentials and salts as plain text
+	{"wp-config.php.*", SeverityCritical},
+	{"wp-config.php~", SeverityCritical},
+	{".wp-config.php.sw?", SeverityCritical},
+	{"wp-config.bak", SeverityCritical},
+	{"wp-config.old", SeverityCritical},
+	{"wp-config.txt", SeverityCritical},
+	{"*.sql", SeverityHigh},
+	{".git", SeverityHigh},
+	// Migration and database tools left behind after a move
+	{"installer.php", SeverityHigh},
+	{"installer-backup.php", SeverityHigh},
+	{"searchreplacedb2.php", SeverityHigh},
+	{"adminer*.php", SeverityHigh},
+	{"phpinfo.php", SeverityMedium},
+	{"debug.log", SeverityMedium},
+	{"error_log", SeverityLow},
+	// A leftover maintenance flag keeps the site in maintenance mode
+	{".maintenance", SeverityLow},
+}
+
+// DefaultSensitivePatterns returns a copy of the patterns ScanSensitiveFiles
+// uses by default, e.g. to extend them
+func DefaultSensitivePatterns() []SensitivePattern {
+	return slices.Clone(defaultSensitivePatterns)
+}
+
+// SensitiveFile is a file or directory matching a SensitivePattern
+type SensitiveFile struct {
+	// Path is relative to the scanned root
+	Path     string
+	Severity Severity
+}
+
+// ScanSensitiveFiles walks the tree at root and reports every file or
+// directory whose name matches one of patterns, in lexical order. A nil
+// patterns uses DefaultSensitivePatterns. The first matching pattern sets
+// the severity, and matched directories aren't descended into.
+func ScanSensitiveFiles(fsys fs.FS, root string, patterns []SensitivePattern) ([]SensitiveFile, error) {
+	if patterns == nil {
+		patterns = defaultSensitivePatterns
+	}
+
+	sub, err := fs.Sub(fsys, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", root, err)
+	}
+
+	var found []SensitiveFile
+	err = fs.WalkDir(sub, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+
+		severity, ok := matchSensitive(patterns, d.Name())
+		if !ok {
+			return nil
+		}
+		found = append(found, SensitiveFile{Path: name, Severity: severity})
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for sensitive files: %w", err)
+	}
+
+	return found, nil
+}
+
+func matchSensitive(patterns []SensitivePattern, name string) (Severity, bool) {
+	name = strings.ToLower(name)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p.Pattern, name); ok {
+			return p.Severity, true
+		}
+	}
+	return "", false
+}
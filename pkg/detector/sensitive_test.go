@@ -0,0 +1,74 @@
+package detector_test
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+)
+
+func TestScanSensitiveFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"site/wp-config.php":                    {Data: []byte("<?php")},
+		"site/wp-config.php.bak":                {Data: []byte("<?php define('DB_PASSWORD', 'secret');")},
+		"site/.maintenance":                     {Data: []byte("<?php $upgrading = 1700000000;")},
+		"site/backup/DUMP.SQL":                  {Data: []byte("CREATE TABLE wp_users")},
+		"site/.git/config":                      {Data: []byte("[core]")},
+		"site/wp-admin/install.php":             {Data: []byte("<?php")},
+		"site/wp-content/plugins/akismet/a.php": {Data: []byte("<?php")},
+	}
+
+	got, err := detector.ScanSensitiveFiles(fsys, "site", nil)
+	if err != nil {
+		t.Fatalf("ScanSensitiveFiles() error = %v", err)
+	}
+
+	want := []detector.SensitiveFile{
+		{Path: ".git", Severity: detector.SeverityHigh},
+		{Path: ".maintenance", Severity: detector.SeverityLow},
+		{Path: "backup/DUMP.SQL", Severity: detector.SeverityHigh},
+		{Path: "wp-config.php.bak", Severity: detector.SeverityCritical},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ScanSensitiveFiles() = %v, want %v", got, want)
+	}
+
+	if _, err := detector.ScanSensitiveFiles(fsys, "missing", nil); err == nil {
+		t.Error("Expected an error for a missing root")
+	}
+}
+
+func TestScanSensitiveFiles_CustomPatterns(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wp-config.php.bak":  {Data: []byte("<?php")},
+		"site-backup.tar.gz": {Data: []byte{0x1f, 0x8b}},
+	}
+	archives := detector.SensitivePattern{Pattern: "*.tar.gz", Severity: detector.SeverityHigh}
+
+	got, err := detector.ScanSensitiveFiles(fsys, ".", []detector.SensitivePattern{archives})
+	if err != nil {
+		t.Fatalf("ScanSensitiveFiles() error = %v", err)
+	}
+	want := []detector.SensitiveFile{{Path: "site-backup.tar.gz", Severity: detector.SeverityHigh}}
+	if !slices.Equal(got, want) {
+		t.Errorf("ScanSensitiveFiles() = %v, want %v", got, want)
+	}
+
+	// Extending the defaults leaves them unchanged for other scans
+	extended := append(detector.DefaultSensitivePatterns(), archives)
+	got, err = detector.ScanSensitiveFiles(fsys, ".", extended)
+	if err != nil {
+		t.Fatalf("ScanSensitiveFiles() error = %v", err)
+	}
+	want = []detector.SensitiveFile{
+		{Path: "site-backup.tar.gz", Severity: detector.SeverityHigh},
+		{Path: "wp-config.php.bak", Severity: detector.SeverityCritical},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ScanSensitiveFiles() = %v, want %v", got, want)
+	}
+	if slices.Contains(detector.DefaultSensitivePatterns(), archives) {
+		t.Error("Expected the defaults not to include the appended pattern")
+	}
+}
@@ -0,0 +1,73 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// DetectedTheme is a theme found on disk together with its parsed header
+type DetectedTheme struct {
+	// Slug is the theme directory name
+	Slug    string
+	Name    string
+	Version string
+	// Template is the parent theme slug for child themes
+	Template string
+}
+
+// ScanThemes scans a themes directory (usually wp-content/themes) for
+// directories containing a style.css with a "Theme Name" header
+func ScanThemes(fsys fs.FS, root string) ([]DetectedTheme, error) {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read themes directory: %w", err)
+	}
+
+	var themes []DetectedTheme
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		content, err := readHeaderContent(fsys, path.Join(root, entry.Name(), "style.css"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := wordpress.ParseHeaderField(content, "Theme Name")
+		if name == "" {
+			continue
+		}
+
+		themes = append(themes, DetectedTheme{
+			Slug:     entry.Name(),
+			Name:     name,
+			Version:  wordpress.ParseHeaderField(content, "Version"),
+			Template: wordpress.ParseHeaderField(content, "Template"),
+		})
+	}
+
+	return themes, nil
+}
+
+func readHeaderContent(fsys fs.FS, name string) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(io.LimitReader(f, wordpress.HeaderReadLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return content, nil
+}
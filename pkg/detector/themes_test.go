@@ -0,0 +1,44 @@
+package detector_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+)
+
+func TestScanThemes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wp-content/themes/twentytwentyfour/style.css": {Data: []byte(`/*
+Theme Name: Twenty Twenty-Four
+Theme URI: https://wordpress.org/themes/twentytwentyfour/
+Version: 1.0
+*/`)},
+		"wp-content/themes/child/style.css": {Data: []byte(`/*
+ Theme Name: My Child Theme
+ Template: twentytwentyfour
+ Version: 0.1.0
+*/`)},
+		"wp-content/themes/broken/index.php": {Data: []byte(`<?php`)},
+		"wp-content/themes/index.php":        {Data: []byte(`<?php`)},
+	}
+
+	themes, err := detector.ScanThemes(fsys, "wp-content/themes")
+	if err != nil {
+		t.Fatalf("ScanThemes() error = %v", err)
+	}
+
+	want := []detector.DetectedTheme{
+		{Slug: "child", Name: "My Child Theme", Version: "0.1.0", Template: "twentytwentyfour"},
+		{Slug: "twentytwentyfour", Name: "Twenty Twenty-Four", Version: "1.0"},
+	}
+
+	if len(themes) != len(want) {
+		t.Fatalf("Expected %d themes, got %d: %+v", len(want), len(themes), themes)
+	}
+	for i := range want {
+		if themes[i] != want[i] {
+			t.Errorf("themes[%d] = %+v, want %+v", i, themes[i], want[i])
+		}
+	}
+}
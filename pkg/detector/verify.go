@@ -0,0 +1,106 @@
+package detector
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// ReleaseDownloader downloads official plugin releases. It is implemented
+// by *wordpress.Client.
+type ReleaseDownloader interface {
+	DownloadPluginVersion(ctx context.Context, slug, version string) ([]byte, error)
+}
+
+var _ ReleaseDownloader = (*wordpress.Client)(nil)
+
+// VerifyResult compares an installed plugin with the official release of
+// the version its header claims
+type VerifyResult struct {
+	Slug    string
+	Version string
+	// VersionExists is false when WordPress.org has no release of the
+	// claimed version; nothing else is compared then
+	VersionExists bool
+	// OfficialName is the plugin name in the header of the official main
+	// plugin file, or "" if the release doesn't contain that file
+	OfficialName string
+	NameMatches  bool
+	// Changes lists the files that differ from the official release:
+	// added files exist only on disk, removed files only in the release
+	Changes []FileChange
+}
+
+// Tampered reports whether the installed plugin differs from the official
+// release in any way
+func (r VerifyResult) Tampered() bool {
+	return !r.VersionExists || !r.NameMatches || len(r.Changes) > 0
+}
+
+// VerifyAgainstOfficial downloads the official release of the version
+// claimed by p's header and compares it with the installed copy: the
+// plugin name in the main file's header and the content hash of every
+// file. p must be a regular plugin installed in a directory below root in
+// fsys, as returned by ScanPlugins. A plugin reusing the slug of an
+// official one with a different header or modified files is a strong sign
+// of tampering.
+func VerifyAgainstOfficial(ctx context.Context, client ReleaseDownloader, fsys fs.FS, root string, p DetectedPlugin) (VerifyResult, error) {
+	result := VerifyResult{Slug: p.Slug, Version: p.Version}
+
+	dir, mainFile := path.Split(p.Path)
+	if p.Type != TypePlugin || dir == "" {
+		return result, fmt.Errorf("%s: only plugins installed in a directory can be verified", p.Path)
+	}
+	if p.Version == "" {
+		return result, fmt.Errorf("%s: plugin header has no version", p.Path)
+	}
+
+	data, err := client.DownloadPluginVersion(ctx, p.Slug, p.Version)
+	if wordpress.IsNotFound(err) {
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to download official release: %w", err)
+	}
+	result.VersionExists = true
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return result, fmt.Errorf("failed to read official release: %w", err)
+	}
+	official, err := fs.Sub(zr, p.Slug)
+	if err != nil {
+		return result, err
+	}
+
+	header, err := readPluginHeader(official, mainFile)
+	switch {
+	case err == nil:
+		result.OfficialName = header.Name
+		result.NameMatches = header.Name == p.Name
+	case !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, wordpress.ErrNoPluginHeader):
+		return result, err
+	}
+
+	officialHashes, err := hashTree(official)
+	if err != nil {
+		return result, fmt.Errorf("failed to hash official release: %w", err)
+	}
+	installed, err := fs.Sub(fsys, path.Join(root, dir))
+	if err != nil {
+		return result, err
+	}
+	installedHashes, err := hashTree(installed)
+	if err != nil {
+		return result, fmt.Errorf("failed to hash installed plugin: %w", err)
+	}
+
+	result.Changes = diffHashes(officialHashes, installedHashes)
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package detector_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// fakeReleases serves official releases keyed by "slug version"
+type fakeReleases map[string][]byte
+
+func (r fakeReleases) DownloadPluginVersion(ctx context.Context, slug, version string) ([]byte, error) {
+	data, ok := r[slug+" "+version]
+	if !ok {
+		return nil, &wordpress.APIError{StatusCode: 404}
+	}
+	return data, nil
+}
+
+func buildRelease(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyAgainstOfficial(t *testing.T) {
+	const header = "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.7.2\n*/"
+	releases := fakeReleases{
+		"hello-dolly 1.7.2": buildRelease(t, map[string]string{
+			"hello-dolly/hello.php":  header,
+			"hello-dolly/readme.txt": "=== Hello Dolly ===",
+		}),
+	}
+
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantExists   bool
+		wantName     bool
+		wantChanges  []string
+		wantTampered bool
+	}{
+		{
+			name: "untouched",
+			files: fstest.MapFS{
+				"plugins/hello-dolly/hello.php":  {Data: []byte(header)},
+				"plugins/hello-dolly/readme.txt": {Data: []byte("=== Hello Dolly ===")},
+			},
+			wantExists: true,
+			wantName:   true,
+		},
+		{
+			name: "modified and injected files",
+			files: fstest.MapFS{
+				"plugins/hello-dolly/hello.php":  {Data: []byte(header + "\neval($_POST['x']);")},
+				"plugins/hello-dolly/readme.txt": {Data: []byte("=== Hello Dolly ===")},
+				"plugins/hello-dolly/shell.php":  {Data: []byte("<?php system($_GET['c']);")},
+			},
+			wantExists:   true,
+			wantName:     true,
+			wantChanges:  []string{"hello.php", "shell.php"},
+			wantTampered: true,
+		},
+		{
+			name: "renamed",
+			files: fstest.MapFS{
+				"plugins/hello-dolly/hello.php":  {Data: []byte("<?php\n/*\nPlugin Name: Hello Darling\nVersion: 1.7.2\n*/")},
+				"plugins/hello-dolly/readme.txt": {Data: []byte("=== Hello Dolly ===")},
+			},
+			wantExists:   true,
+			wantChanges:  []string{"hello.php"},
+			wantTampered: true,
+		},
+		{
+			name: "unreleased version",
+			files: fstest.MapFS{
+				"plugins/hello-dolly/hello.php": {Data: []byte("<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 9.9\n*/")},
+			},
+			wantTampered: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugins, err := detector.ScanPlugins(tt.files, "plugins")
+			if err != nil || len(plugins) != 1 {
+				t.Fatalf("ScanPlugins() = %v, %v", plugins, err)
+			}
+
+			result, err := detector.VerifyAgainstOfficial(context.Background(), releases, tt.files, "plugins", plugins[0])
+			if err != nil {
+				t.Fatalf("VerifyAgainstOfficial() error = %v", err)
+			}
+
+			if result.VersionExists != tt.wantExists {
+				t.Errorf("VersionExists = %v, want %v", result.VersionExists, tt.wantExists)
+			}
+			if result.NameMatches != tt.wantName {
+				t.Errorf("NameMatches = %v, want %v (official %q)", result.NameMatches, tt.wantName, result.OfficialName)
+			}
+			var changed []string
+			for _, c := range result.Changes {
+				changed = append(changed, c.Path)
+			}
+			if !slices.Equal(changed, tt.wantChanges) {
+				t.Errorf("Changes = %v, want %v", changed, tt.wantChanges)
+			}
+			if result.Tampered() != tt.wantTampered {
+				t.Errorf("Tampered() = %v, want %v", result.Tampered(), tt.wantTampered)
+			}
+		})
+	}
+}
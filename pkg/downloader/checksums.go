@@ -0,0 +1,59 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Checksums maps extracted file paths, relative to the plugin directory, to
+// their hex-encoded SHA-256 digest
+type Checksums map[string]string
+
+// WithChecksums records the SHA-256 digest of every extracted file in sums,
+// which must not be nil. Files are hashed while they are written, so the
+// archive is still read only once.
+func WithChecksums(sums Checksums) ExtractOption {
+	return func(o *extractOptions) {
+		o.checksums = sums
+	}
+}
+
+// WriteTo writes the checksums in the format of sha256sum, sorted by path,
+// so the manifest can be checked with "sha256sum -c" from the plugin
+// directory
+func (c Checksums) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(c)) {
+		fmt.Fprintf(&sb, "%s  %s\n", c[name], name)
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// WriteFile writes the checksums manifest to name
+func (c Checksums) WriteFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create checksums file: %w", err)
+	}
+
+	if _, err := c.WriteTo(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write checksums file: %w", err)
+	}
+	return f.Close()
+}
+
+// checksumPath returns the path an archive entry is recorded under: its
+// path inside the top-level plugin directory
+func checksumPath(name string) string {
+	if _, rest, ok := strings.Cut(name, "/"); ok {
+		return rest
+	}
+	return name
+}
@@ -0,0 +1,55 @@
+package downloader_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/downloader"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestExtractZipStream_WithChecksums(t *testing.T) {
+	data := buildZip(t,
+		zipEntry{name: "hello-dolly/", mode: fs.ModeDir | 0755},
+		zipEntry{name: "hello-dolly/hello.php", content: helloHeader},
+		zipEntry{name: "hello-dolly/includes/lyrics.php", content: "<?php // lyrics"},
+	)
+
+	sums := make(downloader.Checksums)
+	err := downloader.ExtractZipStream(bytes.NewReader(data), int64(len(data)), t.TempDir(),
+		downloader.WithLayout(downloader.LayoutVersioned, "1.7.2"),
+		downloader.WithChecksums(sums),
+	)
+	if err != nil {
+		t.Fatalf("ExtractZipStream() error = %v", err)
+	}
+
+	want := downloader.Checksums{
+		"hello.php":           sha256Hex(helloHeader),
+		"includes/lyrics.php": sha256Hex("<?php // lyrics"),
+	}
+	if len(sums) != len(want) {
+		t.Fatalf("Expected %d checksums, got %v", len(want), sums)
+	}
+	for name, sum := range want {
+		if sums[name] != sum {
+			t.Errorf("checksum of %s = %s, want %s", name, sums[name], sum)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := sums.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	wantManifest := sha256Hex(helloHeader) + "  hello.php\n" + sha256Hex("<?php // lyrics") + "  includes/lyrics.php\n"
+	if buf.String() != wantManifest {
+		t.Errorf("WriteTo() =\n%s\nwant\n%s", buf.String(), wantManifest)
+	}
+}
@@ -0,0 +1,126 @@
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// DownloadAndExtract downloads a plugin archive to a temporary file, checks
+// that it is a valid plugin ZIP and extracts it into outputDir with opts. It
+// returns the size of the downloaded archive. The temporary file is always
+// removed.
+//
+// Extraction is atomic: files are written to a temporary directory inside
+// outputDir and only moved into place, replacing a previous extraction,
+// once every file was extracted. A failed extraction leaves outputDir
+// untouched.
+func DownloadAndExtract(ctx context.Context, client *wordpress.Client, plugin wordpress.PluginInfo, outputDir string, opts ...ExtractOption) (int64, error) {
+	size, _, err := downloadAndExtract(ctx, client, plugin, outputDir, "", opts)
+	return size, err
+}
+
+// downloadAndExtract is DownloadAndExtract that also returns the
+// hex-encoded SHA-256 digest of the archive. When wantDigest is set, an
+// archive with a different digest is rejected before anything is extracted.
+func downloadAndExtract(ctx context.Context, client *wordpress.Client, plugin wordpress.PluginInfo, outputDir, wantDigest string, opts []ExtractOption) (int64, string, error) {
+	if plugin.DownloadLink == "" {
+		return 0, "", fmt.Errorf("plugin %q has no download link", plugin.Slug)
+	}
+
+	tmp, err := os.CreateTemp("", "wp-plugin-*.zip")
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := client.DownloadPluginTo(ctx, plugin.DownloadLink, tmp)
+	if err != nil {
+		return size, "", fmt.Errorf("download failed: %w", err)
+	}
+
+	// Make sure we actually received a plugin archive
+	if err := wordpress.ValidatePluginZipReader(tmp, size, plugin.Slug); err != nil {
+		return size, "", err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, io.NewSectionReader(tmp, 0, size)); err != nil {
+		return size, "", fmt.Errorf("failed to hash archive: %w", err)
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+	if wantDigest != "" && !strings.EqualFold(digest, wantDigest) {
+		return size, "", fmt.Errorf("%w: %s is %s, expected %s", ErrDigestMismatch, plugin.Slug, digest, wantDigest)
+	}
+
+	if err := extractAtomically(tmp, size, outputDir, plugin.Slug, opts); err != nil {
+		return size, "", err
+	}
+
+	return size, digest, nil
+}
+
+// extractAtomically extracts the archive into a temporary directory next to
+// its destination and moves the result into outputDir on success
+func extractAtomically(r io.ReaderAt, size int64, outputDir, slug string, opts []ExtractOption) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Created inside outputDir so that moving the result is a rename on the
+	// same file system
+	tmpDir, err := os.MkdirTemp(outputDir, ".tmp-"+slug+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := ExtractZipStream(r, size, tmpDir, opts...); err != nil {
+		return err
+	}
+
+	o := extractOptions{layout: LayoutSlug}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		return fmt.Errorf("failed to read temporary directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		rel := entry.Name()
+		// Only replace this version, not every version of the plugin
+		if o.layout == LayoutVersioned && entry.IsDir() {
+			rel = filepath.Join(rel, o.version)
+		}
+		if err := replace(filepath.Join(tmpDir, rel), filepath.Join(outputDir, rel)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replace moves src to dst, removing whatever is at dst first
+func replace(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return fmt.Errorf("failed to remove previous %s: %w", dst, err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("failed to move extracted files into place: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+// Package downloader downloads WordPress plugin archives and extracts them to
+// disk
+package downloader
+
+import (
+	"archive/zip"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ErrUnsafePath is returned when an archive entry would be written outside
+// the output directory or through a symbolic link
+var ErrUnsafePath = errors.New("unsafe path in archive")
+
+type extractOptions struct {
+	layout    Layout
+	version   string
+	checksums Checksums
+	limits    Limits
+	// discardTimes leaves extracted files with the time they were written
+	discardTimes bool
+	// fileMode and dirMode replace the permissions recorded in the archive
+	// when set
+	fileMode fs.FileMode
+	dirMode  fs.FileMode
+}
+
+// ExtractOption customizes how an archive is extracted
+type ExtractOption func(*extractOptions)
+
+// WithLayout places the extracted files according to layout. version is
+// only used by LayoutVersioned, where it is required.
+func WithLayout(layout Layout, version string) ExtractOption {
+	return func(o *extractOptions) {
+		o.layout = layout
+		o.version = version
+	}
+}
+
+// WithPreserveTimes sets whether extracted files and directories get the
+// modification time recorded in the archive, which is the default
+func WithPreserveTimes(preserve bool) ExtractOption {
+	return func(o *extractOptions) {
+		o.discardTimes = !preserve
+	}
+}
+
+// WithFileMode gives every extracted file the permissions mode, regardless
+// of the mode recorded in the archive and of the umask. By default the
+// archive's mode is used, reduced by the umask.
+func WithFileMode(mode fs.FileMode) ExtractOption {
+	return func(o *extractOptions) {
+		o.fileMode = mode.Perm()
+	}
+}
+
+// WithDirMode is like WithFileMode for the directories listed in the
+// archive. Directories that are only implied by a file path are created
+// with mode reduced by the umask.
+func WithDirMode(mode fs.FileMode) ExtractOption {
+	return func(o *extractOptions) {
+		o.dirMode = mode.Perm()
+	}
+}
+
+// ExtractZipStream extracts the ZIP archive of the given size read from r
+// into outputDir. Entries are decompressed one at a time straight to disk, so
+// r can be a file and the archive never needs to be held in memory. Symbolic
+// link entries and entries escaping outputDir are rejected, and archives
+// exceeding the Limits fail with ErrZipBomb.
+func ExtractZipStream(r io.ReaderAt, size int64, outputDir string, opts ...ExtractOption) error {
+	o := extractOptions{layout: LayoutSlug}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if _, err := ParseLayout(string(o.layout)); err != nil {
+		return err
+	}
+	if o.layout == LayoutVersioned && o.version == "" {
+		return fmt.Errorf("the versioned layout requires a version")
+	}
+
+	limits := o.limits.withDefaults()
+
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("failed to read ZIP: %w", err)
+	}
+	if len(zipReader.File) > limits.MaxFiles {
+		return fmt.Errorf("%w: %d files, limit is %d", ErrZipBomb, len(zipReader.File), limits.MaxFiles)
+	}
+
+	remaining := limits.MaxTotalSize
+	var dirs []*zip.File
+	for _, file := range zipReader.File {
+		name := o.layout.rewrite(file.Name, o.version)
+		if name == "" {
+			continue
+		}
+		n, err := o.extractFile(file, name, outputDir, min(limits.MaxFileSize, remaining))
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+		}
+		remaining -= n
+
+		if file.FileInfo().IsDir() {
+			dirs = append(dirs, file)
+		}
+	}
+
+	// Writing into a directory updates its modification time, so
+	// directories are stamped last, innermost first. A forced mode is
+	// applied at the same point, as it may not allow writing.
+	for _, dir := range slices.Backward(dirs) {
+		if err := o.finishDir(filepath.Join(outputDir, o.layout.rewrite(dir.Name, o.version)), dir); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", dir.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// finishDir applies the forced mode and the recorded modification time to
+// the extracted directory name
+func (o *extractOptions) finishDir(name string, dir *zip.File) error {
+	if o.dirMode != 0 {
+		if err := os.Chmod(name, o.dirMode); err != nil {
+			return err
+		}
+	}
+	if o.discardTimes {
+		return nil
+	}
+	return setModTime(name, dir)
+}
+
+// setModTime sets the access and modification times of name to the
+// modification time of file, if the archive records one
+func setModTime(name string, file *zip.File) error {
+	if file.Modified.IsZero() {
+		return nil
+	}
+	return os.Chtimes(name, file.Modified, file.Modified)
+}
+
+// extractFile writes file to name below outputDir and returns the number of
+// bytes written. Files larger than limit fail with ErrZipBomb.
+func (o *extractOptions) extractFile(file *zip.File, name, outputDir string, limit int64) (int64, error) {
+	// Prevent path traversal attacks, including through a rewritten name
+	filePath := filepath.Join(outputDir, name)
+	if !filepath.HasPrefix(filePath, filepath.Clean(outputDir)+string(os.PathSeparator)) {
+		return 0, fmt.Errorf("%w: %s", ErrUnsafePath, file.Name)
+	}
+
+	// A symlink entry could point anywhere, including outside outputDir
+	if file.Mode()&fs.ModeSymlink != 0 {
+		return 0, fmt.Errorf("%w: symbolic link %s", ErrUnsafePath, file.Name)
+	}
+
+	// Never follow a symlink already present in the output directory
+	if info, err := os.Lstat(filePath); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		return 0, fmt.Errorf("%w: %s is a symbolic link", ErrUnsafePath, filePath)
+	}
+
+	if file.FileInfo().IsDir() {
+		return 0, os.MkdirAll(filePath, cmp.Or(o.dirMode, file.Mode().Perm())|0700)
+	}
+
+	// Create parent directory
+	if err := os.MkdirAll(filepath.Dir(filePath), cmp.Or(o.dirMode, 0755)|0700); err != nil {
+		return 0, err
+	}
+
+	// The declared size can't be trusted, but lets obvious bombs fail early
+	if file.UncompressedSize64 > uint64(limit) {
+		return 0, fmt.Errorf("%w: %s expands to %d bytes", ErrZipBomb, file.Name, file.UncompressedSize64)
+	}
+
+	// Extract file
+	rc, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, cmp.Or(o.fileMode, file.Mode().Perm()))
+	if err != nil {
+		return 0, err
+	}
+	// The umask and an existing file's mode must not weaken a forced mode
+	if o.fileMode != 0 {
+		if err := f.Chmod(o.fileMode); err != nil {
+			f.Close()
+			return 0, err
+		}
+	}
+
+	var w io.Writer = f
+	var h hash.Hash
+	if o.checksums != nil {
+		h = sha256.New()
+		w = io.MultiWriter(f, h)
+	}
+
+	// Read one byte past the limit to tell a file of exactly limit bytes
+	// from a larger one
+	n, err := io.Copy(w, io.LimitReader(rc, limit+1))
+	if err != nil {
+		f.Close()
+		return n, err
+	}
+	if err := f.Close(); err != nil {
+		return n, err
+	}
+	if n > limit {
+		return n, fmt.Errorf("%w: %s expands to more than %d bytes", ErrZipBomb, file.Name, limit)
+	}
+	if !o.discardTimes {
+		if err := setModTime(filePath, file); err != nil {
+			return n, err
+		}
+	}
+
+	if h != nil {
+		o.checksums[checksumPath(file.Name)] = hex.EncodeToString(h.Sum(nil))
+	}
+	return n, nil
+}
@@ -0,0 +1,422 @@
+package downloader_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/downloader"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+type zipEntry struct {
+	name     string
+	content  string
+	mode     fs.FileMode
+	modified time.Time
+}
+
+// buildZip creates an in-memory ZIP archive from the given entries
+func buildZip(tb testing.TB, entries ...zipEntry) []byte {
+	tb.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate, Modified: e.modified}
+		if e.mode != 0 {
+			header.SetMode(e.mode)
+		}
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.content)); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		tb.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+const helloHeader = "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.7.2\n*/"
+
+func TestExtractZipStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []zipEntry
+		wantErr  error
+		wantFile string
+	}{
+		{
+			name: "plugin archive",
+			entries: []zipEntry{
+				{name: "hello-dolly/", mode: fs.ModeDir | 0755},
+				{name: "hello-dolly/hello.php", content: helloHeader},
+				{name: "hello-dolly/includes/lyrics.php", content: "<?php"},
+			},
+			wantFile: "hello-dolly/includes/lyrics.php",
+		},
+		{
+			name: "path traversal",
+			entries: []zipEntry{
+				{name: "../evil.php", content: "<?php"},
+			},
+			wantErr: downloader.ErrUnsafePath,
+		},
+		{
+			name: "symbolic link",
+			entries: []zipEntry{
+				{name: "hello-dolly/passwd", content: "/etc/passwd", mode: fs.ModeSymlink | 0777},
+			},
+			wantErr: downloader.ErrUnsafePath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildZip(t, tt.entries...)
+			outputDir := t.TempDir()
+
+			err := downloader.ExtractZipStream(bytes.NewReader(data), int64(len(data)), outputDir)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExtractZipStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantFile == "" {
+				return
+			}
+			if _, err := os.Stat(filepath.Join(outputDir, tt.wantFile)); err != nil {
+				t.Errorf("Expected extracted file: %v", err)
+			}
+		})
+	}
+}
+
+func TestExtractZipStream_PreserveTimes(t *testing.T) {
+	dirTime := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	fileTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	data := buildZip(t,
+		zipEntry{name: "hello-dolly/", mode: fs.ModeDir | 0755, modified: dirTime},
+		zipEntry{name: "hello-dolly/hello.php", content: helloHeader, modified: fileTime},
+	)
+
+	tests := []struct {
+		name     string
+		opts     []downloader.ExtractOption
+		wantKept bool
+	}{
+		{name: "default", wantKept: true},
+		{name: "opted out", opts: []downloader.ExtractOption{downloader.WithPreserveTimes(false)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			if err := downloader.ExtractZipStream(bytes.NewReader(data), int64(len(data)), outputDir, tt.opts...); err != nil {
+				t.Fatalf("ExtractZipStream() error = %v", err)
+			}
+
+			for name, want := range map[string]time.Time{"hello-dolly": dirTime, "hello-dolly/hello.php": fileTime} {
+				info, err := os.Stat(filepath.Join(outputDir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if kept := info.ModTime().Equal(want); kept != tt.wantKept {
+					t.Errorf("%s mtime = %v, archive has %v", name, info.ModTime(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractZipStream_Modes(t *testing.T) {
+	data := buildZip(t,
+		zipEntry{name: "hello-dolly/", mode: fs.ModeDir | 0777},
+		zipEntry{name: "hello-dolly/hello.php", content: helloHeader, mode: 0777},
+		zipEntry{name: "hello-dolly/readme.txt", content: "=== Hello Dolly ===", mode: 0600},
+	)
+
+	outputDir := t.TempDir()
+	opts := []downloader.ExtractOption{downloader.WithFileMode(0644), downloader.WithDirMode(0750)}
+	if err := downloader.ExtractZipStream(bytes.NewReader(data), int64(len(data)), outputDir, opts...); err != nil {
+		t.Fatalf("ExtractZipStream() error = %v", err)
+	}
+
+	for name, want := range map[string]fs.FileMode{
+		"hello-dolly":            0750,
+		"hello-dolly/hello.php":  0644,
+		"hello-dolly/readme.txt": 0644,
+	} {
+		info, err := os.Stat(filepath.Join(outputDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestExtractZipStream_ExistingSymlink(t *testing.T) {
+	outputDir := t.TempDir()
+	target := filepath.Join(t.TempDir(), "target.php")
+	if err := os.WriteFile(target, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, filepath.Join(outputDir, "hello.php")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	data := buildZip(t, zipEntry{name: "hello.php", content: "overwritten"})
+	err := downloader.ExtractZipStream(bytes.NewReader(data), int64(len(data)), outputDir)
+	if !errors.Is(err, downloader.ErrUnsafePath) {
+		t.Fatalf("Expected ErrUnsafePath, got %v", err)
+	}
+
+	got, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "original" {
+		t.Errorf("Symlink target was modified: %q", got)
+	}
+}
+
+func TestExtractZipStream_Limits(t *testing.T) {
+	// Zeros compress to almost nothing
+	zeros := strings.Repeat("\x00", 2<<20)
+
+	tests := []struct {
+		name    string
+		entries []zipEntry
+		limits  downloader.Limits
+		wantErr error
+	}{
+		{
+			name:    "file too large",
+			entries: []zipEntry{{name: "bomb/zeros.bin", content: zeros}},
+			limits:  downloader.Limits{MaxFileSize: 1 << 20},
+			wantErr: downloader.ErrZipBomb,
+		},
+		{
+			name: "archive too large",
+			entries: []zipEntry{
+				{name: "bomb/a.bin", content: zeros},
+				{name: "bomb/b.bin", content: zeros},
+			},
+			limits:  downloader.Limits{MaxTotalSize: 3 << 20},
+			wantErr: downloader.ErrZipBomb,
+		},
+		{
+			name: "too many files",
+			entries: []zipEntry{
+				{name: "bomb/a.php", content: "<?php"},
+				{name: "bomb/b.php", content: "<?php"},
+			},
+			limits:  downloader.Limits{MaxFiles: 1},
+			wantErr: downloader.ErrZipBomb,
+		},
+		{
+			name:    "within the limits",
+			entries: []zipEntry{{name: "bomb/zeros.bin", content: zeros}},
+			limits:  downloader.Limits{MaxFileSize: 2 << 20, MaxTotalSize: 2 << 20, MaxFiles: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildZip(t, tt.entries...)
+			err := downloader.ExtractZipStream(bytes.NewReader(data), int64(len(data)), t.TempDir(), downloader.WithLimits(tt.limits))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ExtractZipStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDownloadAndExtract(t *testing.T) {
+	helloZip := buildZip(t, zipEntry{name: "hello-dolly/hello.php", content: helloHeader})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hello-dolly.zip":
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write(helloZip)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient()
+
+	outputDir := t.TempDir()
+	plugin := wordpress.PluginInfo{Slug: "hello-dolly", DownloadLink: server.URL + "/hello-dolly.zip"}
+	size, err := downloader.DownloadAndExtract(context.Background(), client, plugin, outputDir)
+	if err != nil {
+		t.Fatalf("DownloadAndExtract() error = %v", err)
+	}
+	if size != int64(len(helloZip)) {
+		t.Errorf("Expected size %d, got %d", len(helloZip), size)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "hello-dolly", "hello.php")); err != nil {
+		t.Errorf("Expected extracted file: %v", err)
+	}
+
+	plugin = wordpress.PluginInfo{Slug: "wrong-slug", DownloadLink: server.URL + "/hello-dolly.zip"}
+	_, err = downloader.DownloadAndExtract(context.Background(), client, plugin, t.TempDir())
+	if !errors.Is(err, wordpress.ErrInvalidPluginZip) {
+		t.Errorf("Expected ErrInvalidPluginZip, got %v", err)
+	}
+}
+
+// BenchmarkDownloadAndExtract compares buffering the whole archive in memory
+// with streaming it through a temporary file
+func BenchmarkDownloadAndExtract(b *testing.B) {
+	entries := []zipEntry{{name: "big-plugin/big-plugin.php", content: "<?php\n/*\nPlugin Name: Big Plugin\n*/"}}
+	// Random content doesn't compress, so the archive is as large as the
+	// extracted files
+	for i := range 200 {
+		content := make([]byte, 64*1024)
+		rand.Read(content)
+		entries = append(entries, zipEntry{
+			name:    fmt.Sprintf("big-plugin/assets/file%03d.bin", i),
+			content: string(content),
+		})
+	}
+	data := buildZip(b, entries...)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient()
+	plugin := wordpress.PluginInfo{Slug: "big-plugin", DownloadLink: server.URL + "/big-plugin.zip"}
+	ctx := context.Background()
+
+	b.Run("in-memory", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			buf, err := client.DownloadPlugin(ctx, plugin.DownloadLink)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := downloader.ExtractZipStream(bytes.NewReader(buf), int64(len(buf)), b.TempDir()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("temp-file", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := downloader.DownloadAndExtract(ctx, client, plugin, b.TempDir()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestDownloadAndExtract_Atomic(t *testing.T) {
+	valid := buildZip(t,
+		zipEntry{name: "hello-dolly/hello.php", content: helloHeader},
+		zipEntry{name: "hello-dolly/lyrics.txt", content: "Hello, Dolly"},
+	)
+	// The symlink entry makes extraction fail after hello.php was written
+	broken := buildZip(t,
+		zipEntry{name: "hello-dolly/hello.php", content: helloHeader},
+		zipEntry{name: "hello-dolly/link", content: "/etc/passwd", mode: fs.ModeSymlink | 0777},
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		if r.URL.Path == "/broken.zip" {
+			w.Write(broken)
+			return
+		}
+		w.Write(valid)
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient()
+	ctx := context.Background()
+
+	t.Run("failure leaves nothing behind", func(t *testing.T) {
+		outputDir := t.TempDir()
+		plugin := wordpress.PluginInfo{Slug: "hello-dolly", DownloadLink: server.URL + "/broken.zip"}
+
+		if _, err := downloader.DownloadAndExtract(ctx, client, plugin, outputDir); !errors.Is(err, downloader.ErrUnsafePath) {
+			t.Fatalf("Expected ErrUnsafePath, got %v", err)
+		}
+
+		entries, err := os.ReadDir(outputDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("Expected an empty output directory, found %v", entries)
+		}
+	})
+
+	t.Run("replaces a previous extraction", func(t *testing.T) {
+		outputDir := t.TempDir()
+		stale := filepath.Join(outputDir, "hello-dolly", "stale.php")
+		if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(stale, []byte("<?php"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		plugin := wordpress.PluginInfo{Slug: "hello-dolly", DownloadLink: server.URL + "/hello-dolly.zip"}
+		if _, err := downloader.DownloadAndExtract(ctx, client, plugin, outputDir); err != nil {
+			t.Fatalf("DownloadAndExtract() error = %v", err)
+		}
+
+		if _, err := os.Stat(stale); !os.IsNotExist(err) {
+			t.Errorf("Expected stale file to be removed, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, "hello-dolly", "lyrics.txt")); err != nil {
+			t.Errorf("Expected extracted file: %v", err)
+		}
+	})
+
+	t.Run("versioned keeps other versions", func(t *testing.T) {
+		outputDir := t.TempDir()
+		other := filepath.Join(outputDir, "hello-dolly", "1.6", "hello.php")
+		if err := os.MkdirAll(filepath.Dir(other), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(other, []byte("<?php"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		plugin := wordpress.PluginInfo{Slug: "hello-dolly", Version: "1.7.2", DownloadLink: server.URL + "/hello-dolly.zip"}
+		if _, err := downloader.DownloadAndExtract(ctx, client, plugin, outputDir, downloader.WithLayout(downloader.LayoutVersioned, plugin.Version)); err != nil {
+			t.Fatalf("DownloadAndExtract() error = %v", err)
+		}
+
+		for _, name := range []string{other, filepath.Join(outputDir, "hello-dolly", "1.7.2", "hello.php")} {
+			if _, err := os.Stat(name); err != nil {
+				t.Errorf("Expected %s to exist: %v", name, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package downloader
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/fs"
+)
+
+// OpenPluginZip returns a read-only fs.FS over the contents of a plugin
+// archive, so it can be scanned, e.g. with detector.ScanPlugins, without
+// extracting it to disk. Directories missing from the archive are implied
+// by the paths of its files.
+func OpenPluginZip(data []byte) (fs.FS, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ZIP: %w", err)
+	}
+	return zr, nil
+}
@@ -0,0 +1,53 @@
+package downloader_test
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/detector"
+	"github.com/masahiro331/go-wp-detector/pkg/downloader"
+)
+
+func TestOpenPluginZip(t *testing.T) {
+	data := buildZip(t,
+		zipEntry{name: "hello-dolly/hello.php", content: helloHeader},
+		zipEntry{name: "hello-dolly/includes/lyrics.php", content: "<?php"},
+	)
+
+	fsys, err := downloader.OpenPluginZip(data)
+	if err != nil {
+		t.Fatalf("OpenPluginZip() error = %v", err)
+	}
+
+	var files []string
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+
+	want := []string{"hello-dolly/hello.php", "hello-dolly/includes/lyrics.php"}
+	if !slices.Equal(files, want) {
+		t.Errorf("WalkDir() = %v, want %v", files, want)
+	}
+
+	plugins, err := detector.ScanPlugins(fsys, ".")
+	if err != nil {
+		t.Fatalf("ScanPlugins() error = %v", err)
+	}
+	if len(plugins) != 1 || plugins[0].Slug != "hello-dolly" || plugins[0].Version != "1.7.2" {
+		t.Errorf("Unexpected plugins: %+v", plugins)
+	}
+
+	if _, err := downloader.OpenPluginZip([]byte("<html>")); err == nil {
+		t.Error("Expected an error for a non-ZIP input")
+	}
+}
@@ -0,0 +1,63 @@
+package downloader
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Layout controls where the files of an extracted plugin are placed under
+// the output directory
+type Layout string
+
+const (
+	// LayoutSlug keeps the archive structure: <output>/<slug>/...
+	LayoutSlug Layout = "slug"
+	// LayoutVersioned adds the version below the slug so several versions
+	// can be kept side by side: <output>/<slug>/<version>/...
+	LayoutVersioned Layout = "versioned"
+	// LayoutFlat strips the top-level slug directory: <output>/...
+	LayoutFlat Layout = "flat"
+)
+
+// ParseLayout parses a layout name as accepted by the -layout flag
+func ParseLayout(s string) (Layout, error) {
+	switch l := Layout(s); l {
+	case LayoutSlug, LayoutVersioned, LayoutFlat:
+		return l, nil
+	default:
+		return "", fmt.Errorf("unknown layout %q (want slug, versioned or flat)", s)
+	}
+}
+
+// Dir returns the directory a plugin is extracted to with this layout
+func (l Layout) Dir(outputDir, slug, version string) string {
+	switch l {
+	case LayoutVersioned:
+		return filepath.Join(outputDir, slug, version)
+	case LayoutFlat:
+		return outputDir
+	default:
+		return filepath.Join(outputDir, slug)
+	}
+}
+
+// rewrite maps an archive entry name to its path relative to the output
+// directory. It returns "" for entries that have no place in the layout,
+// such as the top-level directory itself in the flat layout.
+func (l Layout) rewrite(name, version string) string {
+	top, rest, ok := strings.Cut(name, "/")
+	if !ok {
+		return name
+	}
+
+	switch l {
+	case LayoutVersioned:
+		return path.Join(top, version, rest)
+	case LayoutFlat:
+		return rest
+	default:
+		return name
+	}
+}
@@ -0,0 +1,98 @@
+package downloader_test
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/downloader"
+)
+
+func TestExtractZipStream_Layout(t *testing.T) {
+	data := buildZip(t,
+		zipEntry{name: "hello-dolly/", mode: fs.ModeDir | 0755},
+		zipEntry{name: "hello-dolly/hello.php", content: helloHeader},
+		zipEntry{name: "hello-dolly/includes/lyrics.php", content: "<?php"},
+	)
+
+	tests := []struct {
+		layout    downloader.Layout
+		wantFiles []string
+		wantDir   string
+	}{
+		{
+			layout:    downloader.LayoutSlug,
+			wantFiles: []string{"hello-dolly/hello.php", "hello-dolly/includes/lyrics.php"},
+			wantDir:   "hello-dolly",
+		},
+		{
+			layout:    downloader.LayoutVersioned,
+			wantFiles: []string{"hello-dolly/1.7.2/hello.php", "hello-dolly/1.7.2/includes/lyrics.php"},
+			wantDir:   "hello-dolly/1.7.2",
+		},
+		{
+			layout:    downloader.LayoutFlat,
+			wantFiles: []string{"hello.php", "includes/lyrics.php"},
+			wantDir:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.layout), func(t *testing.T) {
+			outputDir := t.TempDir()
+
+			err := downloader.ExtractZipStream(bytes.NewReader(data), int64(len(data)), outputDir, downloader.WithLayout(tt.layout, "1.7.2"))
+			if err != nil {
+				t.Fatalf("ExtractZipStream() error = %v", err)
+			}
+
+			for _, name := range tt.wantFiles {
+				if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+					t.Errorf("Expected extracted file %s: %v", name, err)
+				}
+			}
+
+			if got, want := tt.layout.Dir(outputDir, "hello-dolly", "1.7.2"), filepath.Join(outputDir, tt.wantDir); got != want {
+				t.Errorf("Dir() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestExtractZipStream_LayoutPathTraversal(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		layout  downloader.Layout
+		version string
+	}{
+		{"flat", "hello-dolly/../../evil.php", downloader.LayoutFlat, ""},
+		{"versioned entry", "hello-dolly/../../../evil.php", downloader.LayoutVersioned, "1.0"},
+		{"versioned version", "hello-dolly/evil.php", downloader.LayoutVersioned, "../../.."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildZip(t, zipEntry{name: tt.entry, content: "<?php"})
+
+			err := downloader.ExtractZipStream(bytes.NewReader(data), int64(len(data)), t.TempDir(), downloader.WithLayout(tt.layout, tt.version))
+			if !errors.Is(err, downloader.ErrUnsafePath) {
+				t.Errorf("Expected ErrUnsafePath, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	for _, s := range []string{"slug", "versioned", "flat"} {
+		if _, err := downloader.ParseLayout(s); err != nil {
+			t.Errorf("ParseLayout(%q) error = %v", s, err)
+		}
+	}
+	if _, err := downloader.ParseLayout("nested"); err == nil {
+		t.Error("Expected error for unknown layout")
+	}
+}
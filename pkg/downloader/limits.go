@@ -0,0 +1,48 @@
+package downloader
+
+import "errors"
+
+// ErrZipBomb is returned when an archive exceeds the extraction limits,
+// e.g. a tiny compressed entry that expands to gigabytes
+var ErrZipBomb = errors.New("archive exceeds extraction limits")
+
+// Default extraction limits. The largest plugins on WordPress.org are well
+// below them.
+const (
+	DefaultMaxFileSize  = 256 << 20
+	DefaultMaxTotalSize = 1 << 30
+	DefaultMaxFiles     = 50000
+)
+
+// Limits bounds what an archive may expand to. Zero fields use the
+// defaults.
+type Limits struct {
+	// MaxFileSize is the largest uncompressed size of a single file
+	MaxFileSize int64
+	// MaxTotalSize is the largest uncompressed size of the whole archive
+	MaxTotalSize int64
+	// MaxFiles is the largest number of entries in the archive
+	MaxFiles int
+}
+
+// WithLimits sets the extraction limits. Exceeding any of them aborts the
+// extraction with ErrZipBomb.
+func WithLimits(limits Limits) ExtractOption {
+	return func(o *extractOptions) {
+		o.limits = limits
+	}
+}
+
+// withDefaults returns l with zero fields set to the defaults
+func (l Limits) withDefaults() Limits {
+	if l.MaxFileSize <= 0 {
+		l.MaxFileSize = DefaultMaxFileSize
+	}
+	if l.MaxTotalSize <= 0 {
+		l.MaxTotalSize = DefaultMaxTotalSize
+	}
+	if l.MaxFiles <= 0 {
+		l.MaxFiles = DefaultMaxFiles
+	}
+	return l
+}
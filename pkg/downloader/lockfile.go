@@ -0,0 +1,128 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// ErrDigestMismatch is returned when a downloaded archive doesn't match the
+// digest recorded in a lockfile
+var ErrDigestMismatch = errors.New("archive digest mismatch")
+
+// PluginSpec names a plugin to download
+type PluginSpec struct {
+	Slug string
+	// Version is the exact version to download, or empty for the latest
+	Version string
+	// SHA256 is the expected hex-encoded digest of the archive, if known
+	SHA256 string
+}
+
+// Lockfile records exactly what DownloadSet downloaded, so the same set can
+// be reproduced later from Specs
+type Lockfile struct {
+	Plugins []LockedPlugin `json:"plugins"`
+}
+
+// LockedPlugin is a plugin resolved to a concrete version
+type LockedPlugin struct {
+	Slug    string `json:"slug"`
+	Version string `json:"version"`
+	// SHA256 is the hex-encoded digest of the downloaded archive
+	SHA256 string `json:"sha256"`
+}
+
+// Specs returns specs pinning every plugin to its locked version and
+// digest
+func (l Lockfile) Specs() []PluginSpec {
+	specs := make([]PluginSpec, 0, len(l.Plugins))
+	for _, p := range l.Plugins {
+		specs = append(specs, PluginSpec{Slug: p.Slug, Version: p.Version, SHA256: p.SHA256})
+	}
+	return specs
+}
+
+// WriteFile writes the lockfile as indented JSON to name
+func (l Lockfile) WriteFile(name string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+
+	if err := os.WriteFile(name, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// ReadLockfile reads a lockfile written by Lockfile.WriteFile
+func ReadLockfile(name string) (Lockfile, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return Lockfile{}, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var l Lockfile
+	if err := json.Unmarshal(data, &l); err != nil {
+		return Lockfile{}, fmt.Errorf("failed to decode lockfile: %w", err)
+	}
+	return l, nil
+}
+
+// DownloadSet downloads and extracts each plugin of specs into
+// outputDir/<slug> and returns a lockfile recording the version each one
+// resolved to and the digest of its archive. Passing the lockfile's Specs
+// to a later DownloadSet fetches exactly the same versions and fails with
+// ErrDigestMismatch if an archive changed. The first failure stops the
+// download.
+func DownloadSet(ctx context.Context, client *wordpress.Client, specs []PluginSpec, outputDir string) (Lockfile, error) {
+	lock := Lockfile{Plugins: make([]LockedPlugin, 0, len(specs))}
+
+	for _, spec := range specs {
+		plugin, err := resolveSpec(ctx, client, spec)
+		if err != nil {
+			return Lockfile{}, fmt.Errorf("failed to resolve %s: %w", spec.Slug, err)
+		}
+
+		_, digest, err := downloadAndExtract(ctx, client, plugin, outputDir, spec.SHA256, nil)
+		if err != nil {
+			return Lockfile{}, fmt.Errorf("failed to download %s %s: %w", plugin.Slug, plugin.Version, err)
+		}
+
+		lock.Plugins = append(lock.Plugins, LockedPlugin{
+			Slug:    plugin.Slug,
+			Version: plugin.Version,
+			SHA256:  digest,
+		})
+	}
+
+	return lock, nil
+}
+
+// resolveSpec returns the plugin version spec refers to, with its download
+// link
+func resolveSpec(ctx context.Context, client *wordpress.Client, spec PluginSpec) (wordpress.PluginInfo, error) {
+	slug, err := wordpress.NormalizeSlug(spec.Slug)
+	if err != nil {
+		return wordpress.PluginInfo{}, err
+	}
+
+	if spec.Version == "" {
+		info, err := client.GetPluginInfo(ctx, slug)
+		if err != nil {
+			return wordpress.PluginInfo{}, err
+		}
+		return wordpress.PluginInfo{Slug: slug, Version: info.Version, DownloadLink: info.DownloadLink}, nil
+	}
+
+	downloadURL, err := client.VersionDownloadURL(ctx, slug, spec.Version)
+	if err != nil {
+		return wordpress.PluginInfo{}, err
+	}
+	return wordpress.PluginInfo{Slug: slug, Version: spec.Version, DownloadLink: downloadURL}, nil
+}
@@ -0,0 +1,107 @@
+package downloader_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/downloader"
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestDownloadSet(t *testing.T) {
+	archives := map[string][]byte{
+		"/plugin/hello-dolly.1.7.2.zip": buildZip(t, zipEntry{name: "hello-dolly/hello.php", content: helloHeader}),
+		"/plugin/hello-dolly.1.6.zip":   buildZip(t, zipEntry{name: "hello-dolly/hello.php", content: "<?php\n/*\nPlugin Name: Hello Dolly\nVersion: 1.6\n*/"}),
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/info/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{
+			Slug:         "hello-dolly",
+			Version:      "1.7.2",
+			DownloadLink: server.URL + "/plugin/hello-dolly.1.7.2.zip",
+			Versions: wordpress.Versions{
+				"1.6":   server.URL + "/plugin/hello-dolly.1.6.zip",
+				"1.7.2": server.URL + "/plugin/hello-dolly.1.7.2.zip",
+			},
+		})
+	})
+	mux.HandleFunc("/plugin/", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := archives[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(data)
+	})
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL+"/info/"),
+		wordpress.WithDownloadsBaseURL(server.URL+"/plugin/"),
+	)
+
+	// "latest" is resolved to the concrete version
+	outputDir := t.TempDir()
+	lock, err := downloader.DownloadSet(context.Background(), client, []downloader.PluginSpec{{Slug: "hello-dolly"}}, outputDir)
+	if err != nil {
+		t.Fatalf("DownloadSet() error = %v", err)
+	}
+	want := downloader.LockedPlugin{
+		Slug:    "hello-dolly",
+		Version: "1.7.2",
+		SHA256:  sha256Hex(string(archives["/plugin/hello-dolly.1.7.2.zip"])),
+	}
+	if len(lock.Plugins) != 1 || lock.Plugins[0] != want {
+		t.Fatalf("DownloadSet() = %+v, want %+v", lock.Plugins, want)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "hello-dolly", "hello.php")); err != nil {
+		t.Errorf("Expected the plugin to be extracted: %v", err)
+	}
+
+	// The lockfile round-trips and reproduces the same set
+	lockPath := filepath.Join(t.TempDir(), "plugins.lock.json")
+	if err := lock.WriteFile(lockPath); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	read, err := downloader.ReadLockfile(lockPath)
+	if err != nil {
+		t.Fatalf("ReadLockfile() error = %v", err)
+	}
+	again, err := downloader.DownloadSet(context.Background(), client, read.Specs(), t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadSet() from lockfile error = %v", err)
+	}
+	if len(again.Plugins) != 1 || again.Plugins[0] != want {
+		t.Errorf("DownloadSet() from lockfile = %+v, want %+v", again.Plugins, want)
+	}
+
+	// A pinned older version is fetched even though it isn't the latest
+	old, err := downloader.DownloadSet(context.Background(), client, []downloader.PluginSpec{{Slug: "hello-dolly", Version: "1.6"}}, t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadSet() pinned error = %v", err)
+	}
+	if old.Plugins[0].Version != "1.6" || old.Plugins[0].SHA256 != sha256Hex(string(archives["/plugin/hello-dolly.1.6.zip"])) {
+		t.Errorf("Unexpected pinned lock entry %+v", old.Plugins[0])
+	}
+
+	// A changed archive is rejected before it is extracted
+	tamperedDir := t.TempDir()
+	tampered := []downloader.PluginSpec{{Slug: "hello-dolly", Version: "1.7.2", SHA256: sha256Hex("something else")}}
+	if _, err := downloader.DownloadSet(context.Background(), client, tampered, tamperedDir); !errors.Is(err, downloader.ErrDigestMismatch) {
+		t.Errorf("Expected ErrDigestMismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tamperedDir, "hello-dolly")); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing to be extracted, got %v", err)
+	}
+}
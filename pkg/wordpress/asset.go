@@ -0,0 +1,58 @@
+package wordpress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxAssetSize bounds the size of images downloaded with DownloadAsset
+const maxAssetSize = 10 << 20
+
+// ErrAssetTooLarge is returned by DownloadAsset for images larger than
+// 10 MiB
+var ErrAssetTooLarge = errors.New("asset too large")
+
+// DownloadAsset downloads a plugin image such as an icon, banner or
+// screenshot, e.g. from BestIconURL, and returns its content and content
+// type
+func (c *Client) DownloadAsset(ctx context.Context, assetURL string) ([]byte, string, error) {
+	if assetURL == "" {
+		return nil, "", fmt.Errorf("asset URL cannot be empty")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, assetURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do("DownloadAsset", req)
+	if err != nil {
+		return nil, "", c.requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", &statusError{code: resp.StatusCode}
+	}
+	if resp.ContentLength > maxAssetSize {
+		return nil, "", fmt.Errorf("%w: %d bytes", ErrAssetTooLarge, resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAssetSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: failed to read asset: %w", ErrRequestFailed, err)
+	}
+	if len(data) > maxAssetSize {
+		return nil, "", fmt.Errorf("%w: more than %d bytes", ErrAssetTooLarge, maxAssetSize)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	return data, contentType, nil
+}
@@ -0,0 +1,61 @@
+package wordpress_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// onePixelPNG is a valid 1x1 transparent PNG
+var onePixelPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00\x1f\x15\xc4\x89\x00\x00\x00\rIDATx\x9cc\x00\x01\x00\x00\x05\x00\x01\r\n-\xb4\x00\x00\x00\x00IEND\xaeB`\x82")
+
+func TestClient_DownloadAsset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/icon-128x128.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(onePixelPNG)
+		case "/untyped":
+			// Without a Content-Type the type is sniffed
+			w.Header()["Content-Type"] = nil
+			w.Write(onePixelPNG)
+		case "/huge.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write(bytes.Repeat([]byte{0xff}, 11<<20))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient()
+	info := wordpress.PluginInfo{Icons: wordpress.Icons{OneX: server.URL + "/icon-128x128.png"}}
+
+	data, contentType, err := client.DownloadAsset(context.Background(), info.BestIconURL())
+	if err != nil {
+		t.Fatalf("DownloadAsset() error = %v", err)
+	}
+	if !bytes.Equal(data, onePixelPNG) {
+		t.Errorf("Unexpected content: %q", data)
+	}
+	if contentType != "image/png" {
+		t.Errorf("Expected content type image/png, got %q", contentType)
+	}
+
+	if _, contentType, err = client.DownloadAsset(context.Background(), server.URL+"/untyped"); err != nil || contentType != "image/png" {
+		t.Errorf("Expected sniffed image/png, got %q, %v", contentType, err)
+	}
+
+	if _, _, err := client.DownloadAsset(context.Background(), server.URL+"/huge.jpg"); !errors.Is(err, wordpress.ErrAssetTooLarge) {
+		t.Errorf("Expected ErrAssetTooLarge, got %v", err)
+	}
+
+	if _, _, err := client.DownloadAsset(context.Background(), server.URL+"/missing.png"); !wordpress.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
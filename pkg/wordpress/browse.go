@@ -0,0 +1,123 @@
+package wordpress
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// Browse modes of the query_plugins API
+const (
+	BrowsePopular  = "popular"
+	BrowseFeatured = "featured"
+	BrowseUpdated  = "updated"
+	BrowseNew      = "new"
+	// BrowseBeta lists plugins in the beta testing program
+	BrowseBeta = "beta"
+	// BrowseBlocks lists plugins that provide blocks for the block editor
+	BrowseBlocks = "blocks"
+	// BrowseFavorites lists the plugins a WordPress.org user marked as
+	// favorite; use QueryFavorites, which sends the required user
+	BrowseFavorites = "favorites"
+)
+
+func validateBrowse(browse string) error {
+	switch browse {
+	case BrowsePopular, BrowseFeatured, BrowseUpdated, BrowseNew, BrowseBeta, BrowseBlocks:
+		return nil
+	case BrowseFavorites:
+		return fmt.Errorf("browse mode %q requires a user, use QueryFavorites", browse)
+	default:
+		return fmt.Errorf("unknown browse mode %q", browse)
+	}
+}
+
+// QueryFavorites lists the plugins the WordPress.org user has marked as
+// favorite
+func (c *Client) QueryFavorites(ctx context.Context, user string, perPage, page int, opts ...QueryOption) (*QueryPluginsResponse, error) {
+	if user == "" {
+		return nil, fmt.Errorf("user cannot be empty")
+	}
+
+	params := url.Values{
+		"request[browse]": {BrowseFavorites},
+		"request[user]":   {user},
+	}
+	return c.queryPlugins(ctx, "QueryFavorites", params, perPage, page, opts)
+}
+
+// SearchPlugins searches the plugin directory for keyword, returning the
+// matches ordered by relevance
+func (c *Client) SearchPlugins(ctx context.Context, keyword string, perPage, page int, opts ...QueryOption) (*QueryPluginsResponse, error) {
+	if keyword == "" {
+		return nil, fmt.Errorf("search keyword cannot be empty")
+	}
+
+	return c.queryPlugins(ctx, "SearchPlugins", url.Values{"request[search]": {keyword}}, perPage, page, opts)
+}
+
+// QueryPluginsByTag lists the plugins tagged with tag, a tag slug such as
+// "contact-form"
+func (c *Client) QueryPluginsByTag(ctx context.Context, tag string, perPage, page int, opts ...QueryOption) (*QueryPluginsResponse, error) {
+	if tag == "" {
+		return nil, fmt.Errorf("tag cannot be empty")
+	}
+
+	return c.queryPlugins(ctx, "QueryPluginsByTag", url.Values{"request[tag]": {tag}}, perPage, page, opts)
+}
+
+// QueryMerged fetches the first page of each browse mode concurrently and
+// merges them into a single list without duplicate slugs. Plugins are
+// ordered by their position in their list, ties going to the earlier browse
+// mode, and a plugin listed more than once is kept at its best position.
+func (c *Client) QueryMerged(ctx context.Context, browses []string, perPage int) ([]PluginInfo, error) {
+	if len(browses) == 0 {
+		return nil, fmt.Errorf("at least one browse mode is required")
+	}
+
+	var (
+		wg    sync.WaitGroup
+		pages = make([][]PluginInfo, len(browses))
+		errs  = make([]error, len(browses))
+	)
+	for i, browse := range browses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.QueryPlugins(ctx, browse, perPage, 1)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to query %s plugins: %w", browse, err)
+				return
+			}
+			pages[i] = resp.Plugins
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	longest := 0
+	for _, plugins := range pages {
+		longest = max(longest, len(plugins))
+	}
+
+	var (
+		merged []PluginInfo
+		seen   = make(map[string]bool)
+	)
+	for rank := range longest {
+		for _, plugins := range pages {
+			if rank >= len(plugins) || seen[plugins[rank].Slug] {
+				continue
+			}
+			seen[plugins[rank].Slug] = true
+			merged = append(merged, plugins[rank])
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,153 @@
+package wordpress_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_QueryFavorites(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"info": {"page": 1, "pages": 1, "results": 1}, "plugins": [{"slug": "akismet"}]}`))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	resp, err := client.QueryFavorites(context.Background(), "matt", 10, 2)
+	if err != nil {
+		t.Fatalf("QueryFavorites() error = %v", err)
+	}
+	if len(resp.Plugins) != 1 {
+		t.Errorf("Expected 1 plugin, got %d", len(resp.Plugins))
+	}
+
+	want := url.Values{
+		"action":            {"query_plugins"},
+		"request[browse]":   {"favorites"},
+		"request[user]":     {"matt"},
+		"request[per_page]": {"10"},
+		"request[page]":     {"2"},
+	}
+	if query.Encode() != want.Encode() {
+		t.Errorf("Unexpected query %s, want %s", query.Encode(), want.Encode())
+	}
+
+	if _, err := client.QueryFavorites(context.Background(), "", 10, 1); err == nil {
+		t.Error("Expected error for an empty user")
+	}
+}
+
+func TestClient_QueryPlugins_BrowseModes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"info": {"page": 1, "pages": 1, "results": 0}, "plugins": []}`))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	tests := []struct {
+		browse  string
+		wantErr bool
+	}{
+		{wordpress.BrowsePopular, false},
+		{wordpress.BrowseFeatured, false},
+		{wordpress.BrowseUpdated, false},
+		{wordpress.BrowseNew, false},
+		{wordpress.BrowseBeta, false},
+		{wordpress.BrowseBlocks, false},
+		{wordpress.BrowseFavorites, true},
+		{"trending", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.browse, func(t *testing.T) {
+			_, err := client.QueryPlugins(context.Background(), tt.browse, 1, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryPlugins(%q) error = %v, wantErr %v", tt.browse, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_SearchPlugins(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"info": {"page": 1, "pages": 1, "results": 1}, "plugins": [{"slug": "envira-gallery-lite"}]}`))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	resp, err := client.SearchPlugins(context.Background(), "gallery", 5, 1)
+	if err != nil {
+		t.Fatalf("SearchPlugins() error = %v", err)
+	}
+	if len(resp.Plugins) != 1 {
+		t.Errorf("Expected 1 plugin, got %d", len(resp.Plugins))
+	}
+
+	want := url.Values{
+		"action":            {"query_plugins"},
+		"request[search]":   {"gallery"},
+		"request[per_page]": {"5"},
+		"request[page]":     {"1"},
+	}
+	if query.Encode() != want.Encode() {
+		t.Errorf("Unexpected query %s, want %s", query.Encode(), want.Encode())
+	}
+
+	if _, err := client.SearchPlugins(context.Background(), "", 5, 1); err == nil {
+		t.Error("Expected an error for an empty keyword")
+	}
+}
+
+func TestClient_QueryMerged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("request[browse]") {
+		case "featured":
+			w.Write([]byte(`{"info": {"page": 1, "pages": 1, "results": 3}, "plugins": [{"slug": "akismet"}, {"slug": "jetpack"}, {"slug": "gutenberg"}]}`))
+		case "popular":
+			w.Write([]byte(`{"info": {"page": 1, "pages": 9, "results": 90}, "plugins": [{"slug": "elementor"}, {"slug": "akismet"}]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	plugins, err := client.QueryMerged(context.Background(), []string{"featured", "popular"}, 10)
+	if err != nil {
+		t.Fatalf("QueryMerged() error = %v", err)
+	}
+
+	var got []string
+	for _, p := range plugins {
+		got = append(got, p.Slug)
+	}
+	want := []string{"akismet", "elementor", "jetpack", "gutenberg"}
+	if !slices.Equal(got, want) {
+		t.Errorf("QueryMerged() = %v, want %v", got, want)
+	}
+
+	if _, err := client.QueryMerged(context.Background(), []string{"featured", "new"}, 10); err == nil || !strings.Contains(err.Error(), "new") {
+		t.Errorf("Expected an error naming the failed browse mode, got %v", err)
+	}
+	if _, err := client.QueryMerged(context.Background(), nil, 10); err == nil {
+		t.Error("Expected an error without browse modes")
+	}
+}
@@ -0,0 +1,119 @@
+package wordpress
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// CacheStats reports how effective the response cache is
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Evictions counts entries dropped to stay within the entry limit;
+	// expired entries are not counted
+	Evictions uint64
+}
+
+// WithCache caches successful API responses (QueryPlugins, GetPluginInfo
+// and the methods built on them) in memory for ttl. At most maxEntries
+// responses are kept; the least recently used one is evicted when the limit
+// is exceeded. A ttl or maxEntries of 0 or less disables that bound.
+// Downloads are never cached.
+func WithCache(ttl time.Duration, maxEntries int) ClientOption {
+	return func(c *Client) {
+		c.cache = newResponseCache(ttl, maxEntries)
+	}
+}
+
+// CacheStats returns the response cache counters. It returns zero stats
+// when no cache is configured.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.stats()
+}
+
+// responseCache is a concurrency-safe LRU cache with per-entry expiry
+type responseCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	lru        *list.List // front is most recently used
+	entries    map[string]*list.Element
+	counters   CacheStats
+
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	key     string
+	data    []byte
+	expires time.Time
+}
+
+func newResponseCache(ttl time.Duration, maxEntries int) *responseCache {
+	return &responseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+func (rc *responseCache) get(key string) ([]byte, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	elem, ok := rc.entries[key]
+	if !ok {
+		rc.counters.Misses++
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if rc.ttl > 0 && !rc.now().Before(entry.expires) {
+		rc.remove(elem)
+		rc.counters.Misses++
+		return nil, false
+	}
+
+	rc.lru.MoveToFront(elem)
+	rc.counters.Hits++
+	return entry.data, true
+}
+
+func (rc *responseCache) add(key string, data []byte) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	expires := rc.now().Add(rc.ttl)
+	if elem, ok := rc.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.data = data
+		entry.expires = expires
+		rc.lru.MoveToFront(elem)
+		return
+	}
+
+	rc.entries[key] = rc.lru.PushFront(&cacheEntry{key: key, data: data, expires: expires})
+
+	for rc.maxEntries > 0 && rc.lru.Len() > rc.maxEntries {
+		rc.remove(rc.lru.Back())
+		rc.counters.Evictions++
+	}
+}
+
+func (rc *responseCache) remove(elem *list.Element) {
+	rc.lru.Remove(elem)
+	delete(rc.entries, elem.Value.(*cacheEntry).key)
+}
+
+func (rc *responseCache) stats() CacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return rc.counters
+}
@@ -0,0 +1,168 @@
+package wordpress_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestResponseCache_Eviction(t *testing.T) {
+	rc := wordpress.NewResponseCache(0, 2, time.Now)
+
+	rc.Add("a", []byte("A"))
+	rc.Add("b", []byte("B"))
+	// Touch a so that b becomes the least recently used entry
+	if _, ok := rc.Get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	rc.Add("c", []byte("C"))
+
+	if _, ok := rc.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := rc.Get(key); !ok {
+			t.Errorf("Expected %s to be cached", key)
+		}
+	}
+
+	want := wordpress.CacheStats{Hits: 3, Misses: 1, Evictions: 1}
+	if got := rc.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestResponseCache_TTL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rc := wordpress.NewResponseCache(time.Minute, 0, func() time.Time { return now })
+
+	rc.Add("a", []byte("A"))
+
+	now = now.Add(59 * time.Second)
+	if data, ok := rc.Get("a"); !ok || string(data) != "A" {
+		t.Errorf("Expected a to be cached before expiry, got %q, %v", data, ok)
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := rc.Get("a"); ok {
+		t.Error("Expected a to expire after the TTL")
+	}
+
+	want := wordpress.CacheStats{Hits: 1, Misses: 1}
+	if got := rc.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_WithCache(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: r.URL.Query().Get("request[slug]")})
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL), wordpress.WithCache(time.Minute, 10))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetPluginInfo(context.Background(), "akismet"); err != nil {
+				t.Errorf("GetPluginInfo() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	info, err := client.GetPluginInfo(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if info.Slug != "akismet" {
+		t.Errorf("Expected cached slug akismet, got %s", info.Slug)
+	}
+
+	before := calls.Load()
+	if _, err := client.GetPluginInfo(context.Background(), "hello-dolly"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if calls.Load() != before+1 {
+		t.Error("Expected a different slug not to be served from the cache")
+	}
+
+	stats := client.CacheStats()
+	if stats.Hits == 0 || stats.Hits+stats.Misses != 10 {
+		t.Errorf("Unexpected cache stats %+v", stats)
+	}
+	if got := wordpress.NewClient().CacheStats(); got != (wordpress.CacheStats{}) {
+		t.Errorf("Expected zero stats without a cache, got %+v", got)
+	}
+}
+
+func TestWithDiskCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet", Version: "5.5"})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	newClient := func() *wordpress.Client {
+		return wordpress.NewClient(
+			wordpress.WithBaseURL(server.URL),
+			wordpress.WithDiskCache(dir, time.Hour),
+			wordpress.WithClock(clock),
+		)
+	}
+
+	if _, err := newClient().GetPluginInfo(context.Background(), "akismet"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+
+	// A second client sharing the directory is served from disk
+	info, err := newClient().GetPluginInfo(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if info.Version != "5.5" {
+		t.Errorf("Expected cached version 5.5, got %s", info.Version)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 cache file, got %v", files)
+	}
+
+	// Expired files are pruned and refetched
+	clock.now = clock.now.Add(time.Hour)
+	if err := newClient().PruneDiskCache(); err != nil {
+		t.Fatalf("PruneDiskCache() error = %v", err)
+	}
+	if _, err := os.Stat(files[0]); !os.IsNotExist(err) {
+		t.Errorf("Expected the expired cache file to be removed, got %v", err)
+	}
+	if _, err := newClient().GetPluginInfo(context.Background(), "akismet"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected the expired response to be refetched, got %d requests", got)
+	}
+}
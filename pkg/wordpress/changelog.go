@@ -0,0 +1,74 @@
+package wordpress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// ErrNoChangelog is returned when a plugin's readme has no changelog section
+var ErrNoChangelog = errors.New("plugin has no changelog")
+
+var (
+	htmlHeading   = regexp.MustCompile(`(?i)<h[1-6][^>]*>`)
+	htmlLineBreak = regexp.MustCompile(`(?i)<br\s*/?>|</(?:p|li|h[1-6]|ul|ol|div)>`)
+	htmlListItem  = regexp.MustCompile(`(?i)<li[^>]*>`)
+	htmlTag       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// sectionBreak marks where a heading starts while converting HTML to text
+const sectionBreak = "\x00"
+
+// GetPluginChangelog returns the changelog section of a plugin's readme as
+// HTML. Only the readme sections are requested, so this is much cheaper than
+// downloading the plugin archive.
+func (c *Client) GetPluginChangelog(ctx context.Context, slug string) (string, error) {
+	fields := MinimalFields()
+	fields["sections"] = true
+
+	info, err := c.GetPluginInfo(ctx, slug, WithQueryFields(fields))
+	if err != nil {
+		return "", err
+	}
+
+	changelog := info.Sections["changelog"]
+	if strings.TrimSpace(changelog) == "" {
+		return "", fmt.Errorf("%w: %s", ErrNoChangelog, slug)
+	}
+
+	return changelog, nil
+}
+
+// ChangelogText returns the changelog section as plain text. Each heading
+// (usually a release) starts a new paragraph, list items are prefixed with
+// "* " and HTML entities are decoded.
+func (p PluginInfo) ChangelogText() string {
+	return htmlToText(p.Sections["changelog"])
+}
+
+// htmlToText strips the tags from the limited HTML used in readme sections
+func htmlToText(s string) string {
+	s = htmlHeading.ReplaceAllString(s, sectionBreak)
+	s = htmlListItem.ReplaceAllString(s, "* ")
+	s = htmlLineBreak.ReplaceAllString(s, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	var paragraphs []string
+	for _, section := range strings.Split(s, sectionBreak) {
+		var lines []string
+		for _, line := range strings.Split(section, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+		}
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
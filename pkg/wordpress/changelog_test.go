@@ -0,0 +1,81 @@
+package wordpress_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_GetPluginChangelog(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("request[slug]") {
+		case "akismet":
+			w.Write([]byte(`{
+				"name": "Akismet Anti-spam",
+				"slug": "akismet",
+				"sections": {
+					"description": "<p>Akismet checks your comments.</p>",
+					"changelog": "<h4>5.5</h4>\n<p><em>Release Date - 15 July 2025</em></p>\n<ul>\n<li>Improve the display of &quot;spam&quot; counts.</li>\n<li>Fix a PHP 8.4 warning.</li>\n</ul>"
+				}
+			}`))
+		default:
+			w.Write([]byte(`{"name": "No Changelog", "slug": "no-changelog", "sections": {"description": "<p>Hi</p>"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	changelog, err := client.GetPluginChangelog(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("GetPluginChangelog() error = %v", err)
+	}
+	if !strings.HasPrefix(changelog, "<h4>5.5</h4>") {
+		t.Errorf("Expected the changelog HTML, got %q", changelog)
+	}
+
+	for key, want := range map[string]string{
+		"request[fields][sections]":    "1",
+		"request[fields][description]": "0",
+		"request[fields][versions]":    "0",
+	} {
+		if got := query[key]; len(got) != 1 || got[0] != want {
+			t.Errorf("Expected %s=%s, got %v", key, want, got)
+		}
+	}
+
+	_, err = client.GetPluginChangelog(context.Background(), "no-changelog")
+	if !errors.Is(err, wordpress.ErrNoChangelog) {
+		t.Errorf("Expected ErrNoChangelog, got %v", err)
+	}
+}
+
+func TestPluginInfo_ChangelogText(t *testing.T) {
+	info := wordpress.PluginInfo{
+		Sections: wordpress.Sections{
+			"changelog": "<h4>5.5</h4>\n<p><em>Release Date - 15 July 2025</em></p>\n<ul>\n<li>Improve the display of &quot;spam&quot; counts.</li>\n<li>Fix a PHP 8.4 warning.<br />Thanks to a contributor.</li>\n</ul>\n<h4>5.4</h4>\n<ul><li>Initial &amp; final.</li></ul>",
+		},
+	}
+
+	want := `5.5
+Release Date - 15 July 2025
+* Improve the display of "spam" counts.
+* Fix a PHP 8.4 warning.
+Thanks to a contributor.
+
+5.4
+* Initial & final.`
+
+	if got := info.ChangelogText(); got != want {
+		t.Errorf("ChangelogText() =\n%s\nwant\n%s", got, want)
+	}
+}
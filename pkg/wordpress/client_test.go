@@ -1,11 +1,22 @@
 package wordpress_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
 )
@@ -179,6 +190,96 @@ func TestClient_GetPluginInfo(t *testing.T) {
 	}
 }
 
+func TestClient_LatestVersion(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("request[fields][versions]") != "0" {
+			t.Error("Expected optional fields to be disabled")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("request[slug]") != "akismet" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Plugin not found."}`))
+			return
+		}
+		w.Write([]byte(`{"slug":"akismet","name":"Akismet","version":"5.5"}`))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL), wordpress.WithCache(time.Minute, 0))
+
+	version, err := client.LatestVersion(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("LatestVersion() error = %v", err)
+	}
+	if version != "5.5" {
+		t.Errorf("LatestVersion() = %q, want 5.5", version)
+	}
+
+	// PluginExists sends the same request, so it is served from the cache
+	if exists, err := client.PluginExists(context.Background(), "akismet"); err != nil || !exists {
+		t.Errorf("PluginExists() = %v, %v", exists, err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+
+	if _, err := client.LatestVersion(context.Background(), "missing"); !wordpress.IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestClient_PluginExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("request[fields][sections]") != "0" {
+			t.Error("Expected optional fields to be disabled")
+		}
+
+		switch r.URL.Query().Get("request[slug]") {
+		case "akismet":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"slug":"akismet","name":"Akismet"}`))
+		case "missing":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Plugin not found."}`))
+		case "closed":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`false`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	tests := []struct {
+		slug    string
+		want    bool
+		wantErr bool
+	}{
+		{slug: "akismet", want: true},
+		{slug: "missing", want: false},
+		{slug: "closed", want: false},
+		{slug: "broken", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			got, err := client.PluginExists(context.Background(), tt.slug)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PluginExists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PluginExists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestClient_DownloadPlugin(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -234,3 +335,1006 @@ func TestClient_DownloadPlugin(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_DownloadPluginTo_Resume(t *testing.T) {
+	content := bytes.Repeat([]byte("PK\x03\x04plugin-data"), 1024)
+
+	tests := []struct {
+		name          string
+		supportsRange bool
+	}{
+		{
+			name:          "server honors range",
+			supportsRange: true,
+		},
+		{
+			name:          "server ignores range",
+			supportsRange: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRange string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRange = r.Header.Get("Range")
+				if tt.supportsRange {
+					http.ServeContent(w, r, "plugin.zip", time.Time{}, bytes.NewReader(content))
+					return
+				}
+				w.Header().Set("Content-Type", "application/zip")
+				w.Write(content)
+			}))
+			defer server.Close()
+
+			// Simulate an interrupted download
+			path := filepath.Join(t.TempDir(), "plugin.zip")
+			partial := len(content) / 3
+			if err := os.WriteFile(path, content[:partial], 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := os.OpenFile(path, os.O_RDWR, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			client := wordpress.NewClient(wordpress.WithResumableDownload())
+
+			n, err := client.DownloadPluginTo(context.Background(), server.URL+"/plugin.zip", f)
+			if err != nil {
+				t.Fatalf("DownloadPluginTo() error = %v", err)
+			}
+
+			wantRange := fmt.Sprintf("bytes=%d-", partial)
+			if gotRange != wantRange {
+				t.Errorf("Expected Range header %q, got %q", wantRange, gotRange)
+			}
+
+			wantN := int64(len(content) - partial)
+			if !tt.supportsRange {
+				wantN = int64(len(content))
+			}
+			if n != wantN {
+				t.Errorf("Expected %d bytes written, got %d", wantN, n)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("Downloaded file differs from source: got %d bytes, want %d", len(got), len(content))
+			}
+		})
+	}
+}
+
+func TestClient_DownloadPluginTo_WithoutResume(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			t.Error("Range header should not be sent without WithResumableDownload")
+		}
+		w.Write([]byte("PK\x03\x04"))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := wordpress.NewClient()
+	if _, err := client.DownloadPluginTo(context.Background(), server.URL, &buf); err != nil {
+		t.Fatalf("DownloadPluginTo() error = %v", err)
+	}
+	if buf.String() != "PK\x03\x04" {
+		t.Errorf("Unexpected content %q", buf.String())
+	}
+}
+
+func TestClient_DownloadPlugin_NotAZip(t *testing.T) {
+	tests := []struct {
+		name     string
+		zipCheck bool
+		wantErr  bool
+	}{
+		{
+			name:     "html response is rejected",
+			zipCheck: true,
+			wantErr:  true,
+		},
+		{
+			name:     "html response is accepted when the check is disabled",
+			zipCheck: false,
+			wantErr:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.Write([]byte("<html><body>Error</body></html>"))
+			}))
+			defer server.Close()
+
+			client := wordpress.NewClient(wordpress.WithZipCheck(tt.zipCheck))
+
+			_, err := client.DownloadPlugin(context.Background(), server.URL)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DownloadPlugin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, wordpress.ErrNotAZip) {
+				t.Errorf("Expected ErrNotAZip, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	var info wordpress.PluginInfo
+	if err := json.Unmarshal([]byte(`{"slug":"akismet","last_updated":"2024-05-01 3:04pm GMT"}`), &info); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := time.Date(2024, 5, 1, 15, 4, 0, 0, time.UTC)
+	if !info.LastUpdated.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, info.LastUpdated.Time)
+	}
+
+	if err := json.Unmarshal([]byte(`{"last_updated":false}`), &info); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !info.LastUpdated.IsZero() {
+		t.Errorf("Expected zero time, got %v", info.LastUpdated.Time)
+	}
+}
+
+func TestPluginInfo_UnmarshalNumbers(t *testing.T) {
+	tests := []struct {
+		name           string
+		json           string
+		wantRating     wordpress.FlexibleFloat
+		wantNumRatings wordpress.FlexibleInt
+	}{
+		{name: "numbers", json: `{"rating":95,"num_ratings":1200}`, wantRating: 95, wantNumRatings: 1200},
+		{name: "float rating", json: `{"rating":95.5,"num_ratings":1200}`, wantRating: 95.5, wantNumRatings: 1200},
+		{name: "strings", json: `{"rating":"95","num_ratings":"1200"}`, wantRating: 95, wantNumRatings: 1200},
+		{name: "empty", json: `{"rating":"","num_ratings":false}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info wordpress.PluginInfo
+			if err := json.Unmarshal([]byte(tt.json), &info); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if info.Rating != tt.wantRating {
+				t.Errorf("Rating = %v, want %v", info.Rating, tt.wantRating)
+			}
+			if info.NumRatings != tt.wantNumRatings {
+				t.Errorf("NumRatings = %v, want %v", info.NumRatings, tt.wantNumRatings)
+			}
+		})
+	}
+
+	var info wordpress.PluginInfo
+	if err := json.Unmarshal([]byte(`{"rating":"excellent"}`), &info); err == nil {
+		t.Error("Expected an error for a non-numeric rating")
+	}
+}
+
+func TestClient_GetPluginInfos(t *testing.T) {
+	missing := map[string]bool{"does-not-exist": true, "removed-plugin": true}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug := r.URL.Query().Get("request[slug]")
+		if missing[slug] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: slug, Version: "1.0"})
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithRateLimit(1000, 10),
+	)
+
+	slugs := []string{"akismet", "does-not-exist", "jetpack", "removed-plugin", "woocommerce", "wordfence"}
+
+	infos, errs := client.GetPluginInfos(context.Background(), slugs, 3)
+
+	if len(infos) != 4 {
+		t.Errorf("Expected 4 plugin infos, got %d", len(infos))
+	}
+	if len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %d", len(errs))
+	}
+
+	for _, slug := range slugs {
+		if missing[slug] {
+			if errs[slug] == nil {
+				t.Errorf("Expected error for %s", slug)
+			}
+			continue
+		}
+		info, ok := infos[slug]
+		if !ok {
+			t.Errorf("Missing info for %s (error: %v)", slug, errs[slug])
+			continue
+		}
+		if info.Slug != slug {
+			t.Errorf("Expected slug %s, got %s", slug, info.Slug)
+		}
+	}
+}
+
+func TestClient_GetPluginInfos_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Server should not be called after cancellation")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	infos, errs := client.GetPluginInfos(ctx, []string{"akismet", "jetpack"}, 2)
+	if len(infos) != 0 {
+		t.Errorf("Expected no results, got %d", len(infos))
+	}
+	for _, slug := range []string{"akismet", "jetpack"} {
+		if !errors.Is(errs[slug], context.Canceled) {
+			t.Errorf("Expected context.Canceled for %s, got %v", slug, errs[slug])
+		}
+	}
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL), wordpress.WithLogger(logger))
+
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"sending request", "received response", "method=GetPluginInfo", "status=200", server.URL} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestClient_WithLogger_RequestID(t *testing.T) {
+	var gotID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithLogger(logger),
+		wordpress.WithTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			gotID = wordpress.RequestIDFromContext(req.Context())
+			return http.DefaultTransport.RoundTrip(req)
+		})),
+	)
+
+	ctx := wordpress.ContextWithRequestID(context.Background(), "req-42")
+	if _, err := client.GetPluginInfo(ctx, "akismet"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected log output, got:\n%s", buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "request_id=req-42") {
+			t.Errorf("Expected request_id in every log line, got %q", line)
+		}
+	}
+	if gotID != "req-42" {
+		t.Errorf("Expected the transport to see request ID req-42, got %q", gotID)
+	}
+	if id := wordpress.RequestIDFromContext(context.Background()); id != "" {
+		t.Errorf("Expected no request ID, got %q", id)
+	}
+}
+
+func TestClient_GetPluginInfo_BannersAndScreenshots(t *testing.T) {
+	payload := `{
+		"name": "Contact Form 7",
+		"slug": "contact-form-7",
+		"version": "6.1.3",
+		"banners": {
+			"low": "https://ps.w.org/contact-form-7/assets/banner-772x250.png?rev=2279696",
+			"high": "https://ps.w.org/contact-form-7/assets/banner-1544x500.png?rev=2279696"
+		},
+		"screenshots": {
+			"10": {"src": "https://ps.w.org/contact-form-7/assets/screenshot-10.png", "caption": "Tenth"},
+			"2": {"src": "https://ps.w.org/contact-form-7/assets/screenshot-2.png", "caption": "Second"},
+			"1": {"src": "https://ps.w.org/contact-form-7/assets/screenshot-1.png", "caption": "First"}
+		}
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	info, err := client.GetPluginInfo(context.Background(), "contact-form-7")
+	if err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+
+	if info.Banners.High != "https://ps.w.org/contact-form-7/assets/banner-1544x500.png?rev=2279696" {
+		t.Errorf("Unexpected high banner: %s", info.Banners.High)
+	}
+	if info.Banners.Low != "https://ps.w.org/contact-form-7/assets/banner-772x250.png?rev=2279696" {
+		t.Errorf("Unexpected low banner: %s", info.Banners.Low)
+	}
+
+	wantCaptions := []string{"First", "Second", "Tenth"}
+	if len(info.Screenshots) != len(wantCaptions) {
+		t.Fatalf("Expected %d screenshots, got %d", len(wantCaptions), len(info.Screenshots))
+	}
+	for i, want := range wantCaptions {
+		if info.Screenshots[i].Caption != want {
+			t.Errorf("Screenshots[%d].Caption = %s, want %s", i, info.Screenshots[i].Caption, want)
+		}
+	}
+}
+
+func TestPluginInfo_EmptyBannersAndScreenshots(t *testing.T) {
+	var info wordpress.PluginInfo
+	if err := json.Unmarshal([]byte(`{"slug":"hello-dolly","banners":[],"screenshots":[]}`), &info); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if info.Banners != (wordpress.Banners{}) {
+		t.Errorf("Expected empty banners, got %+v", info.Banners)
+	}
+	if len(info.Screenshots) != 0 {
+		t.Errorf("Expected no screenshots, got %+v", info.Screenshots)
+	}
+}
+
+func TestClient_GetPluginInfo_ContributorsAndSupport(t *testing.T) {
+	payload := `{
+		"name": "Akismet Anti-spam: Spam Protection",
+		"slug": "akismet",
+		"version": "5.5",
+		"rating": 92,
+		"num_ratings": 1024,
+		"ratings": {"5": 900, "4": 60, "3": 20, "2": 14, "1": 30},
+		"support_threads": 20,
+		"support_threads_resolved": 15,
+		"contributors": {
+			"automattic": {
+				"profile": "https://profiles.wordpress.org/automattic/",
+				"avatar": "https://secure.gravatar.com/avatar/example?s=96&d=monsterid&r=g",
+				"display_name": "Automattic"
+			},
+			"matt": "https://profiles.wordpress.org/matt/"
+		}
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	info, err := client.GetPluginInfo(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+
+	wantContributors := map[string]string{
+		"automattic": "https://profiles.wordpress.org/automattic/",
+		"matt":       "https://profiles.wordpress.org/matt/",
+	}
+	if len(info.Contributors) != len(wantContributors) {
+		t.Errorf("Expected %d contributors, got %d", len(wantContributors), len(info.Contributors))
+	}
+	for name, profile := range wantContributors {
+		if info.Contributors[name] != profile {
+			t.Errorf("Contributors[%s] = %s, want %s", name, info.Contributors[name], profile)
+		}
+	}
+
+	wantRatings := map[int]int{5: 900, 4: 60, 3: 20, 2: 14, 1: 30}
+	for stars, count := range wantRatings {
+		if info.Ratings[stars] != count {
+			t.Errorf("Ratings[%d] = %d, want %d", stars, info.Ratings[stars], count)
+		}
+	}
+
+	if info.SupportThreads != 20 || info.SupportThreadsResolved != 15 {
+		t.Errorf("Unexpected support stats: %d/%d", info.SupportThreadsResolved, info.SupportThreads)
+	}
+	if rate := info.SupportResolutionRate(); rate != 0.75 {
+		t.Errorf("SupportResolutionRate() = %v, want 0.75", rate)
+	}
+}
+
+func TestPluginInfo_SupportResolutionRate_NoThreads(t *testing.T) {
+	p := wordpress.PluginInfo{Slug: "quiet-plugin"}
+	if rate := p.SupportResolutionRate(); rate != 0 {
+		t.Errorf("SupportResolutionRate() = %v, want 0", rate)
+	}
+}
+
+func TestClient_HeadDownload(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "content length reported",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("Expected HEAD request, got %s", r.Method)
+				}
+				w.Header().Set("Content-Length", "2048")
+			},
+			want: 2048,
+		},
+		{
+			name: "HEAD not allowed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			},
+			want: -1,
+		},
+		{
+			name: "not found",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client := wordpress.NewClient()
+
+			got, err := client.HeadDownload(context.Background(), server.URL+"/plugin.zip")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HeadDownload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("HeadDownload() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_GetPluginInfo_Gzip(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    func() []byte
+		wantErr string
+	}{
+		{
+			name: "gzip encoded body",
+			body: func() []byte {
+				var buf bytes.Buffer
+				zw := gzip.NewWriter(&buf)
+				json.NewEncoder(zw).Encode(wordpress.PluginInfo{Slug: "akismet", Version: "5.5"})
+				zw.Close()
+				return buf.Bytes()
+			},
+		},
+		{
+			name: "malformed gzip body",
+			body: func() []byte {
+				return []byte(`{"slug":"akismet"}`)
+			},
+			wantErr: "malformed gzip response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+					t.Errorf("Expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Write(tt.body())
+			}))
+			defer server.Close()
+
+			client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+			info, err := client.GetPluginInfo(context.Background(), "akismet")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPluginInfo() error = %v", err)
+			}
+			if info.Slug != "akismet" || info.Version != "5.5" {
+				t.Errorf("Unexpected plugin info %+v", info)
+			}
+		})
+	}
+}
+
+func TestQueryPluginsResponse_Pagination(t *testing.T) {
+	tests := []struct {
+		name        string
+		info        wordpress.QueryInfo
+		wantHasNext bool
+		wantNext    int
+		wantTotal   int
+	}{
+		{
+			name:        "first of many pages",
+			info:        wordpress.QueryInfo{Page: 1, Pages: 10, Results: 1000},
+			wantHasNext: true,
+			wantNext:    2,
+			wantTotal:   10,
+		},
+		{
+			name:        "second to last page",
+			info:        wordpress.QueryInfo{Page: 9, Pages: 10, Results: 1000},
+			wantHasNext: true,
+			wantNext:    10,
+			wantTotal:   10,
+		},
+		{
+			name:        "last page",
+			info:        wordpress.QueryInfo{Page: 10, Pages: 10, Results: 1000},
+			wantHasNext: false,
+			wantNext:    0,
+			wantTotal:   10,
+		},
+		{
+			name:        "single page",
+			info:        wordpress.QueryInfo{Page: 1, Pages: 1, Results: 3},
+			wantHasNext: false,
+			wantNext:    0,
+			wantTotal:   1,
+		},
+		{
+			name:        "no results",
+			info:        wordpress.QueryInfo{Page: 1, Pages: 0, Results: 0},
+			wantHasNext: false,
+			wantNext:    0,
+			wantTotal:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &wordpress.QueryPluginsResponse{Info: tt.info}
+
+			if got := r.HasNextPage(); got != tt.wantHasNext {
+				t.Errorf("HasNextPage() = %v, want %v", got, tt.wantHasNext)
+			}
+			if got := r.NextPage(); got != tt.wantNext {
+				t.Errorf("NextPage() = %d, want %d", got, tt.wantNext)
+			}
+			if got := r.TotalPages(); got != tt.wantTotal {
+				t.Errorf("TotalPages() = %d, want %d", got, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestQueryPluginsResponse_UnmarshalJSON(t *testing.T) {
+	array := `{"info": {"page": 1, "pages": 1, "results": 2}, "plugins": [
+		{"name": "Akismet Anti-spam", "slug": "akismet", "version": "5.5"},
+		{"name": "Hello Dolly", "slug": "hello-dolly", "version": "1.7.2"}
+	]}`
+	object := `{"info": {"page": 1, "pages": 1, "results": 2}, "plugins": {
+		"akismet": {"name": "Akismet Anti-spam", "slug": "akismet", "version": "5.5"},
+		"hello-dolly": {"name": "Hello Dolly", "version": "1.7.2"}
+	}}`
+
+	var fromArray, fromObject wordpress.QueryPluginsResponse
+	if err := json.Unmarshal([]byte(array), &fromArray); err != nil {
+		t.Fatalf("Unmarshal(array) error = %v", err)
+	}
+	if err := json.Unmarshal([]byte(object), &fromObject); err != nil {
+		t.Fatalf("Unmarshal(object) error = %v", err)
+	}
+
+	if len(fromArray.Plugins) != 2 {
+		t.Fatalf("Expected 2 plugins, got %d", len(fromArray.Plugins))
+	}
+	if !reflect.DeepEqual(fromArray, fromObject) {
+		t.Errorf("Object form decoded to %+v, want %+v", fromObject, fromArray)
+	}
+
+	for _, payload := range []string{`{"plugins": []}`, `{"plugins": {}}`} {
+		var r wordpress.QueryPluginsResponse
+		if err := json.Unmarshal([]byte(payload), &r); err != nil {
+			t.Errorf("Unmarshal(%s) error = %v", payload, err)
+		}
+		if len(r.Plugins) != 0 {
+			t.Errorf("Unmarshal(%s) returned %d plugins", payload, len(r.Plugins))
+		}
+	}
+
+	var r wordpress.QueryPluginsResponse
+	if err := json.Unmarshal([]byte(`{"plugins": "akismet"}`), &r); err == nil {
+		t.Error("Expected an error for a string plugins value")
+	}
+}
+
+func TestClient_QueryPlugins_KeyedStrict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"info": {"page": 1, "pages": 1, "results": 1}, "plugins": {"akismet": {"slug": "akismet", "renamed_field": true}}}`))
+	}))
+	defer server.Close()
+
+	resp, err := wordpress.NewClient(wordpress.WithBaseURL(server.URL)).QueryPlugins(context.Background(), "popular", 10, 1)
+	if err != nil {
+		t.Fatalf("QueryPlugins() error = %v", err)
+	}
+	if len(resp.Plugins) != 1 || resp.Plugins[0].Slug != "akismet" {
+		t.Errorf("Expected akismet, got %+v", resp.Plugins)
+	}
+
+	strict := wordpress.NewClient(wordpress.WithBaseURL(server.URL), wordpress.WithStrictDecoding())
+	if _, err := strict.QueryPlugins(context.Background(), "popular", 10, 1); !errors.Is(err, wordpress.ErrDecodeFailed) {
+		t.Errorf("Expected strict decoding to reject the unknown field, got %v", err)
+	}
+}
+
+func TestPluginInfo_Icons(t *testing.T) {
+	payload := `{
+		"slug": "akismet",
+		"icons": {
+			"1x": "https://ps.w.org/akismet/assets/icon-128x128.png?rev=2818463",
+			"2x": "https://ps.w.org/akismet/assets/icon-256x256.png?rev=2818463",
+			"svg": "https://ps.w.org/akismet/assets/icon.svg?rev=2818463",
+			"default": "https://ps.w.org/akismet/assets/icon.svg?rev=2818463"
+		}
+	}`
+
+	var info wordpress.PluginInfo
+	if err := json.Unmarshal([]byte(payload), &info); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := wordpress.Icons{
+		OneX:    "https://ps.w.org/akismet/assets/icon-128x128.png?rev=2818463",
+		TwoX:    "https://ps.w.org/akismet/assets/icon-256x256.png?rev=2818463",
+		SVG:     "https://ps.w.org/akismet/assets/icon.svg?rev=2818463",
+		Default: "https://ps.w.org/akismet/assets/icon.svg?rev=2818463",
+	}
+	if info.Icons != want {
+		t.Errorf("Icons = %+v, want %+v", info.Icons, want)
+	}
+}
+
+func TestPluginInfo_BestIconURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		icons wordpress.Icons
+		want  string
+	}{
+		{
+			name:  "prefers svg",
+			icons: wordpress.Icons{OneX: "1x.png", TwoX: "2x.png", SVG: "icon.svg", Default: "default.png"},
+			want:  "icon.svg",
+		},
+		{
+			name:  "then 2x",
+			icons: wordpress.Icons{OneX: "1x.png", TwoX: "2x.png", Default: "default.png"},
+			want:  "2x.png",
+		},
+		{
+			name:  "then 1x",
+			icons: wordpress.Icons{OneX: "1x.png", Default: "default.png"},
+			want:  "1x.png",
+		},
+		{
+			name:  "then default",
+			icons: wordpress.Icons{Default: "default.png"},
+			want:  "default.png",
+		},
+		{
+			name: "no icons",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := wordpress.PluginInfo{Icons: tt.icons}
+			if got := p.BestIconURL(); got != tt.want {
+				t.Errorf("BestIconURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_WithBaseURLs_Failover(t *testing.T) {
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer mirror.Close()
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+
+	t.Run("falls back to the next base URL", func(t *testing.T) {
+		client := wordpress.NewClient(wordpress.WithBaseURLs(unavailable.URL, mirror.URL))
+
+		info, err := client.GetPluginInfo(context.Background(), "akismet")
+		if err != nil {
+			t.Fatalf("GetPluginInfo() error = %v", err)
+		}
+		if info.Slug != "akismet" {
+			t.Errorf("Expected slug akismet, got %s", info.Slug)
+		}
+	})
+
+	t.Run("client errors don't fail over", func(t *testing.T) {
+		client := wordpress.NewClient(wordpress.WithBaseURLs(notFound.URL, mirror.URL))
+
+		if _, err := client.GetPluginInfo(context.Background(), "akismet"); err == nil {
+			t.Fatal("Expected the 404 from the first base URL")
+		}
+	})
+
+	t.Run("all base URLs fail", func(t *testing.T) {
+		client := wordpress.NewClient(wordpress.WithBaseURLs(unavailable.URL, unavailable.URL+"/mirror/"))
+
+		_, err := client.GetPluginInfo(context.Background(), "akismet")
+		if err == nil {
+			t.Fatal("Expected error when every base URL fails")
+		}
+		if !strings.Contains(err.Error(), unavailable.URL+":") || !strings.Contains(err.Error(), unavailable.URL+"/mirror/:") {
+			t.Errorf("Expected every attempt in the error, got %v", err)
+		}
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client := wordpress.NewClient(wordpress.WithBaseURLs(unavailable.URL, mirror.URL))
+		if _, err := client.GetPluginInfo(ctx, "akismet"); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestPluginInfo_Tags(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    []string
+	}{
+		{
+			name: "tags object",
+			payload: `{
+				"slug": "woocommerce",
+				"tags": {
+					"online-store": "online store",
+					"ecommerce": "ecommerce",
+					"shop": "shop",
+					"shopping-cart": "shopping cart",
+					"sell-online": "sell online"
+				}
+			}`,
+			want: []string{"ecommerce", "online-store", "sell-online", "shop", "shopping-cart"},
+		},
+		{
+			name:    "absent",
+			payload: `{"slug": "hello-dolly"}`,
+			want:    nil,
+		},
+		{
+			name:    "empty object",
+			payload: `{"slug": "hello-dolly", "tags": {}}`,
+			want:    nil,
+		},
+		{
+			name:    "empty array",
+			payload: `{"slug": "hello-dolly", "tags": []}`,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info wordpress.PluginInfo
+			if err := json.Unmarshal([]byte(tt.payload), &info); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			got := info.TagSlugs()
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("TagSlugs() = %v, want %v", got, tt.want)
+			}
+			for _, slug := range got {
+				if info.Tags[slug] == "" {
+					t.Errorf("Expected a label for tag %s", slug)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_WithStrictDecoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "Akismet Anti-spam", "slug": "akismet", "version": "5.5", "renamed_field": true}`))
+	}))
+	defer server.Close()
+
+	lenient := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+	info, err := lenient.GetPluginInfo(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if info.Slug != "akismet" {
+		t.Errorf("Expected slug akismet, got %s", info.Slug)
+	}
+
+	strict := wordpress.NewClient(wordpress.WithBaseURL(server.URL), wordpress.WithStrictDecoding())
+	_, err = strict.GetPluginInfo(context.Background(), "akismet")
+	if err == nil {
+		t.Fatal("Expected strict decoding to reject the unknown field")
+	}
+	if !strings.Contains(err.Error(), `"renamed_field"`) {
+		t.Errorf("Expected the error to name the unknown field, got %v", err)
+	}
+}
+
+func TestClient_WithResponseInterceptor(t *testing.T) {
+	// Unusual spacing and an unknown field make sure the body isn't re-encoded
+	payload := []byte("{\"name\":  \"Akismet Anti-spam\",\n \"slug\": \"akismet\", \"version\": \"5.5\", \"new_field\": [1, 2]}")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	var (
+		gotMethod string
+		gotBody   []byte
+	)
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithResponseInterceptor(func(method string, body []byte) {
+			gotMethod = method
+			gotBody = body
+		}),
+	)
+
+	info, err := client.GetPluginInfo(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if info.Slug != "akismet" {
+		t.Errorf("Expected slug akismet, got %s", info.Slug)
+	}
+	if gotMethod != "GetPluginInfo" {
+		t.Errorf("Expected method GetPluginInfo, got %q", gotMethod)
+	}
+	if !bytes.Equal(gotBody, payload) {
+		t.Errorf("Expected the interceptor to receive %q, got %q", payload, gotBody)
+	}
+}
+
+func TestPluginInfo_AuthorName(t *testing.T) {
+	tests := []struct {
+		name   string
+		author string
+		want   string
+	}{
+		{
+			name:   "link",
+			author: `<a href="https://automattic.com/wordpress-plugins/">Automattic - Anti-spam Team</a>`,
+			want:   "Automattic - Anti-spam Team",
+		},
+		{
+			name:   "entities",
+			author: `<a href="https://yoast.com/" rel="nofollow">Team Yoast &amp; Friends</a>`,
+			want:   "Team Yoast & Friends",
+		},
+		{
+			name:   "plain text",
+			author: "Matt Mullenweg",
+			want:   "Matt Mullenweg",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := wordpress.PluginInfo{Author: tt.author}
+			if got := p.AuthorName(); got != tt.want {
+				t.Errorf("AuthorName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluginInfo_UnmarshalAuthorFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantProfile string
+		wantDonate  string
+	}{
+		{
+			name:        "present",
+			data:        `{"author": "<a href=\"https://automattic.com\">Automattic</a>", "author_profile": "https://profiles.wordpress.org/automattic/", "donate_link": "https://example.com/donate"}`,
+			wantProfile: "https://profiles.wordpress.org/automattic/",
+			wantDonate:  "https://example.com/donate",
+		},
+		{
+			name:        "donate link false",
+			data:        `{"author_profile": "https://profiles.wordpress.org/matt/", "donate_link": false}`,
+			wantProfile: "https://profiles.wordpress.org/matt/",
+		},
+		{
+			name: "absent",
+			data: `{"slug": "akismet"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p wordpress.PluginInfo
+			if err := json.Unmarshal([]byte(tt.data), &p); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if p.AuthorProfile != tt.wantProfile || p.DonateLink.String() != tt.wantDonate {
+				t.Errorf("AuthorProfile = %q, DonateLink = %q, want %q, %q", p.AuthorProfile, p.DonateLink, tt.wantProfile, tt.wantDonate)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package wordpress
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of time for retry delays, rate-limit waits and cache
+// expiry
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d or until ctx is done, returning ctx.Err() in the
+	// latter case
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// realClock is the Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error { return sleepContext(ctx, d) }
+
+// WithClock replaces the real time the client waits on, e.g. with a fake
+// clock that makes tests of Retry-After handling and rate limiting run
+// instantly. The clock must be safe for concurrent use.
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
@@ -0,0 +1,68 @@
+package wordpress
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrClientClosed is returned by requests made after Close
+var ErrClientClosed = errors.New("client is closed")
+
+// Close waits for running requests to finish and closes idle connections
+// of the transport the client built for WithProxy, WithTLSConfig,
+// WithConnectionPool or WithDisableHTTP2; a transport or HTTP client passed
+// in, and the default transport, are left alone. A request is running
+// until its response has been read, including downloads and the caching of
+// API responses in WithCache and WithDiskCache; a QueryPluginsStream
+// sequence runs while it is ranged over. The client is unusable
+// afterwards: every request fails with ErrClientClosed. Calling Close more
+// than once is safe.
+func (c *Client) Close() error {
+	c.closeMu.Lock()
+	c.closed = true
+	c.closeMu.Unlock()
+
+	c.inflight.Wait()
+	if c.ownTransport {
+		c.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
+// begin counts a request as running until done is called, or fails with
+// ErrClientClosed once Close was called. done may be called more than
+// once.
+func (c *Client) begin() (done func(), err error) {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+	c.inflight.Add(1)
+	return sync.OnceFunc(c.inflight.Done), nil
+}
+
+// trackedBody ends a request started with begin when the response body is
+// closed
+type trackedBody struct {
+	io.ReadCloser
+	done func()
+}
+
+func (b *trackedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
+
+// untrack ends the request started by do for resp, leaving its body open.
+// It is for bodies whose reading is tracked separately.
+func untrack(resp *http.Response) {
+	if b, ok := resp.Body.(*trackedBody); ok {
+		b.done()
+		resp.Body = b.ReadCloser
+	}
+}
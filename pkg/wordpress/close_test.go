@@ -0,0 +1,189 @@
+package wordpress_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_Close(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet", Version: "5.5"})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL), wordpress.WithDiskCache(dir, 0))
+
+	requestErr := make(chan error)
+	go func() {
+		_, err := client.GetPluginInfo(context.Background(), "akismet")
+		requestErr <- err
+	}()
+	<-started
+
+	closed := make(chan error)
+	go func() { closed <- client.Close() }()
+
+	select {
+	case <-closed:
+		t.Fatal("Close() returned before the in-flight request finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-requestErr; err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// The in-flight response was written to the disk cache
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Errorf("Expected 1 cache file, got %v", files)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Errorf("Second Close() error = %v", err)
+	}
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); !errors.Is(err, wordpress.ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed, got %v", err)
+	}
+	if _, err := client.DownloadPlugin(context.Background(), server.URL+"/akismet.zip"); !errors.Is(err, wordpress.ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed for a download, got %v", err)
+	}
+}
+
+func TestClient_Close_Download(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write([]byte("PK\x03\x04"))
+		w.(http.Flusher).Flush()
+		close(started)
+		<-release
+		w.Write([]byte("hello-dolly"))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient()
+
+	downloaded := make(chan []byte)
+	go func() {
+		data, err := client.DownloadPlugin(context.Background(), server.URL+"/hello-dolly.zip")
+		if err != nil {
+			t.Errorf("DownloadPlugin() error = %v", err)
+		}
+		downloaded <- data
+	}()
+	<-started
+
+	closed := make(chan error)
+	go func() { closed <- client.Close() }()
+
+	select {
+	case <-closed:
+		t.Fatal("Close() returned before the running download finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if data := <-downloaded; string(data) != "PK\x03\x04hello-dolly" {
+		t.Errorf("DownloadPlugin() = %q, want the whole archive", data)
+	}
+	if err := <-closed; err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func TestClient_Close_UnrangedStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.QueryPluginsResponse{
+			Plugins: []wordpress.PluginInfo{{Slug: "akismet"}},
+		})
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	plugins, _, err := client.QueryPluginsStream(context.Background(), wordpress.BrowsePopular, 1, 1)
+	if err != nil {
+		t.Fatalf("QueryPluginsStream() error = %v", err)
+	}
+
+	closed := make(chan error)
+	go func() { closed <- client.Close() }()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close() waited for a sequence that isn't ranged over")
+	}
+
+	for _, err := range plugins {
+		if !errors.Is(err, wordpress.ErrClientClosed) {
+			t.Errorf("Expected ErrClientClosed, got %v", err)
+		}
+	}
+}
+
+// idleCloser records calls to CloseIdleConnections
+type idleCloser struct {
+	http.RoundTripper
+	closed int
+}
+
+func (t *idleCloser) CloseIdleConnections() {
+	t.closed++
+}
+
+func TestClient_Close_CallerTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		option func(http.RoundTripper) wordpress.ClientOption
+	}{
+		{
+			name: "http client",
+			option: func(rt http.RoundTripper) wordpress.ClientOption {
+				return wordpress.WithHTTPClient(&http.Client{Transport: rt})
+			},
+		},
+		{
+			name:   "transport",
+			option: wordpress.WithTransport,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &idleCloser{RoundTripper: http.DefaultTransport}
+			client := wordpress.NewClient(tt.option(transport))
+
+			if err := client.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if transport.closed != 0 {
+				t.Errorf("Close() closed the idle connections of the caller's transport %d times", transport.closed)
+			}
+		})
+	}
+}
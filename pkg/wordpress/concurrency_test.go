@@ -0,0 +1,136 @@
+package wordpress_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// TestClient_Concurrent shares one client, with every option that keeps
+// state between calls, across hundreds of goroutines. Run with -race.
+func TestClient_Concurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slug, ok := strings.CutPrefix(r.URL.Path, "/plugin/"); ok {
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write([]byte("PK\x03\x04" + slug))
+			return
+		}
+
+		q := r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		switch q.Get("action") {
+		case "plugin_information":
+			json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: q.Get("request[slug]")})
+		case "query_plugins":
+			json.NewEncoder(w).Encode(wordpress.QueryPluginsResponse{
+				Info:    wordpress.QueryInfo{Page: 1, Pages: 1, Results: 1},
+				Plugins: []wordpress.PluginInfo{{Slug: "page-" + q.Get("request[page]")}},
+			})
+		}
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithCache(time.Minute, 16),
+		wordpress.WithRateLimit(10000, 100),
+		wordpress.WithMetrics(newFakeMetrics()),
+		wordpress.WithHeader("X-Test", "concurrent"),
+	)
+
+	const goroutines = 300
+	ctx := context.Background()
+	errs := make(chan error, goroutines)
+
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// A small key space makes goroutines hit and evict the same
+			// cache entries
+			n := i % 20
+			switch i % 3 {
+			case 0:
+				slug := fmt.Sprintf("plugin-%d", n)
+				info, err := client.GetPluginInfo(ctx, slug)
+				if err == nil && info.Slug != slug {
+					err = fmt.Errorf("GetPluginInfo(%s) returned %s", slug, info.Slug)
+				}
+				errs <- err
+			case 1:
+				resp, err := client.QueryPlugins(ctx, wordpress.BrowsePopular, 1, n+1)
+				if err == nil && resp.Plugins[0].Slug != fmt.Sprintf("page-%d", n+1) {
+					err = fmt.Errorf("QueryPlugins(page %d) returned %s", n+1, resp.Plugins[0].Slug)
+				}
+				errs <- err
+			case 2:
+				slug := fmt.Sprintf("plugin-%d", n)
+				data, err := client.DownloadPlugin(ctx, server.URL+"/plugin/"+slug)
+				if err == nil && string(data) != "PK\x03\x04"+slug {
+					err = fmt.Errorf("DownloadPlugin(%s) returned %q", slug, data)
+				}
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	if stats := client.CacheStats(); stats.Hits+stats.Misses != 2*goroutines/3 {
+		t.Errorf("Expected every API call to consult the cache, got %+v", stats)
+	}
+}
+
+func TestClient_WithSharedRateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: r.URL.Query().Get("request[slug]")})
+	}))
+	defer server.Close()
+
+	// 20 requests per second without burst: the first request is free and
+	// each of the other nine waits 50ms
+	limiter := rate.NewLimiter(20, 1)
+	clients := []*wordpress.Client{
+		wordpress.NewClient(wordpress.WithBaseURL(server.URL), wordpress.WithSharedRateLimiter(limiter)),
+		wordpress.NewClient(wordpress.WithBaseURL(server.URL), wordpress.WithSharedRateLimiter(limiter)),
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		for j := range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.GetPluginInfo(context.Background(), fmt.Sprintf("plugin-%d-%d", i, j)); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	// Two independent limiters would let the ten requests through in ~200ms
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("Expected the clients to share the limit, 10 requests took %v", elapsed)
+	}
+}
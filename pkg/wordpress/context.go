@@ -0,0 +1,31 @@
+package wordpress
+
+import (
+	"context"
+	"log/slog"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying a correlation ID. The
+// client adds it as request_id to every log line about requests made with
+// the context, and custom transports can read it with RequestIDFromContext.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the correlation ID set with
+// ContextWithRequestID, or "" if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// loggerFor returns the client's logger with the request ID from ctx, if
+// any, attached
+func (c *Client) loggerFor(ctx context.Context) *slog.Logger {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return c.logger.With(slog.String("request_id", id))
+	}
+	return c.logger
+}
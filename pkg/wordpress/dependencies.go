@@ -0,0 +1,59 @@
+package wordpress
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// dependencySlug matches the slugs WordPress accepts in a "Requires Plugins"
+// header (see WP_Plugin_Dependencies::sanitize_dependency_slugs)
+var dependencySlug = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// ParseRequiresPlugins splits a comma-separated "Requires Plugins" value
+// into slugs the way WordPress does: entries are trimmed, invalid slugs and
+// duplicates are dropped and the result is sorted
+func ParseRequiresPlugins(s string) []string {
+	var slugs []string
+	for _, slug := range strings.Split(s, ",") {
+		slug = strings.TrimSpace(slug)
+		if dependencySlug.MatchString(slug) && !slices.Contains(slugs, slug) {
+			slugs = append(slugs, slug)
+		}
+	}
+	slices.Sort(slugs)
+	return slugs
+}
+
+// RequiredPlugins returns the slugs listed in the "Requires Plugins" header
+func (h PluginHeader) RequiredPlugins() []string {
+	return ParseRequiresPlugins(h.RequiresPlugins)
+}
+
+// PluginSlugs is a list of plugin slugs, such as a plugin's dependencies
+type PluginSlugs []string
+
+// UnmarshalJSON implements custom unmarshaling for PluginSlugs, which the
+// API returns either as an array or as the comma-separated header value,
+// and as false or an empty array when there are none
+func (s *PluginSlugs) UnmarshalJSON(data []byte) error {
+	if isEmptyJSONValue(data) {
+		*s = nil
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = ParseRequiresPlugins(str)
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("cannot unmarshal %s into PluginSlugs: %w", string(data), err)
+	}
+	*s = ParseRequiresPlugins(strings.Join(list, ","))
+	return nil
+}
@@ -0,0 +1,73 @@
+package wordpress_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestParseRequiresPlugins(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "woocommerce", want: []string{"woocommerce"}},
+		{input: " woocommerce ,  advanced-custom-fields,", want: []string{"advanced-custom-fields", "woocommerce"}},
+		{input: "woocommerce, woocommerce", want: []string{"woocommerce"}},
+		{input: "WooCommerce, ../etc, my_plugin, jetpack", want: []string{"jetpack"}},
+		{input: "", want: nil},
+		{input: " , ", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := wordpress.ParseRequiresPlugins(tt.input); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseRequiresPlugins(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluginInfo_RequiresPlugins(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    []string
+	}{
+		{name: "array", payload: `{"requires_plugins": ["woocommerce", "jetpack"]}`, want: []string{"jetpack", "woocommerce"}},
+		{name: "comma-separated", payload: `{"requires_plugins": "woocommerce , jetpack"}`, want: []string{"jetpack", "woocommerce"}},
+		{name: "empty array", payload: `{"requires_plugins": []}`},
+		{name: "false", payload: `{"requires_plugins": false}`},
+		{name: "absent", payload: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info wordpress.PluginInfo
+			if err := json.Unmarshal([]byte(tt.payload), &info); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !slices.Equal(info.RequiresPlugins, tt.want) {
+				t.Errorf("RequiresPlugins = %q, want %q", info.RequiresPlugins, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluginHeader_RequiredPlugins(t *testing.T) {
+	header, err := wordpress.ParsePluginHeader([]byte(`<?php
+/**
+ * Plugin Name: WooCommerce Extension
+ * Requires Plugins:  woocommerce,  woocommerce-payments
+ */`))
+	if err != nil {
+		t.Fatalf("ParsePluginHeader() error = %v", err)
+	}
+
+	want := []string{"woocommerce", "woocommerce-payments"}
+	if got := header.RequiredPlugins(); !slices.Equal(got, want) {
+		t.Errorf("RequiredPlugins() = %q, want %q", got, want)
+	}
+}
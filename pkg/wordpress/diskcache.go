@@ -0,0 +1,141 @@
+package wordpress
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WithDiskCache persists successful API responses as pretty-printed JSON
+// files in dir, so repeated runs of a program share them. Each file is
+// named after a hash of the request URL and records when the response was
+// fetched; files older than ttl are ignored and removed when read, and
+// PruneDiskCache removes all of them. A ttl of 0 or less keeps responses
+// forever. It can be combined with WithCache, which is consulted first.
+func WithDiskCache(dir string, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.diskCache = &diskCache{dir: dir, ttl: ttl, now: time.Now}
+	}
+}
+
+// PruneDiskCache removes the expired files from the cache directory
+// configured with WithDiskCache. It does nothing when no disk cache is
+// configured.
+func (c *Client) PruneDiskCache() error {
+	if c.diskCache == nil {
+		return nil
+	}
+	return c.diskCache.prune()
+}
+
+// diskCache stores responses in one file per request URL. Writes go through
+// a temporary file and a rename, so concurrent clients never read a partial
+// file.
+type diskCache struct {
+	dir string
+	ttl time.Duration
+
+	now func() time.Time
+}
+
+// diskCacheEntry is the layout of a cache file
+type diskCacheEntry struct {
+	URL       string          `json:"url"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Response  json.RawMessage `json:"response"`
+}
+
+func (dc *diskCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (dc *diskCache) expired(e diskCacheEntry) bool {
+	return dc.ttl > 0 && !dc.now().Before(e.FetchedAt.Add(dc.ttl))
+}
+
+// get returns the cached response for url. Unreadable and expired files are
+// treated as misses, and expired ones are removed.
+func (dc *diskCache) get(url string) ([]byte, bool) {
+	name := dc.path(url)
+	e, err := readDiskCacheEntry(name)
+	if err != nil || e.URL != url {
+		return nil, false
+	}
+	if dc.expired(e) {
+		os.Remove(name)
+		return nil, false
+	}
+	return e.Response, true
+}
+
+func (dc *diskCache) add(url string, data []byte) error {
+	out, err := json.MarshalIndent(diskCacheEntry{URL: url, FetchedAt: dc.now(), Response: data}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(dc.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	f, err := os.CreateTemp(dc.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	_, err = f.Write(append(out, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), dc.path(url))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+func (dc *diskCache) prune() error {
+	entries, err := os.ReadDir(dc.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		name := filepath.Join(dc.dir, entry.Name())
+		e, err := readDiskCacheEntry(name)
+		if err != nil || !dc.expired(e) {
+			continue
+		}
+		if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove expired cache file: %w", err)
+		}
+	}
+	return nil
+}
+
+func readDiskCacheEntry(name string) (diskCacheEntry, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return diskCacheEntry{}, err
+	}
+	var e diskCacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return diskCacheEntry{}, err
+	}
+	return e, nil
+}
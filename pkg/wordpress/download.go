@@ -0,0 +1,216 @@
+package wordpress
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ErrTrunkUnavailable is returned by DownloadTrunk when a plugin doesn't
+// publish a development build
+var ErrTrunkUnavailable = errors.New("trunk build not available")
+
+// DownloadURL returns the official download URL for a plugin version, e.g.
+// https://downloads.wordpress.org/plugin/akismet.5.5.zip. An empty version
+// returns the URL of the latest stable release.
+func (c *Client) DownloadURL(slug, version string) string {
+	name := slug
+	if version != "" {
+		name += "." + version
+	}
+	return strings.TrimSuffix(c.downloadsBaseURL, "/") + "/" + url.PathEscape(name) + ".zip"
+}
+
+// Download downloads the archive of p from its DownloadLink
+func (c *Client) Download(ctx context.Context, p PluginInfo) ([]byte, error) {
+	if p.DownloadLink == "" {
+		return nil, fmt.Errorf("plugin %q has no download link", p.Slug)
+	}
+	return c.DownloadPlugin(ctx, p.DownloadLink)
+}
+
+// DownloadPluginVersion downloads a specific version of a plugin from the
+// URL returned by VersionDownloadURL
+func (c *Client) DownloadPluginVersion(ctx context.Context, slug, version string) ([]byte, error) {
+	downloadURL, err := c.VersionDownloadURL(ctx, slug, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.DownloadPlugin(ctx, downloadURL)
+}
+
+// VersionDownloadURL returns the download URL of a specific version of a
+// plugin. slug is normalized with NormalizeSlug. The URL is taken from the
+// plugin's Versions map, falling back to DownloadURL when the API doesn't
+// list the version.
+func (c *Client) VersionDownloadURL(ctx context.Context, slug, version string) (string, error) {
+	slug, err := NormalizeSlug(slug)
+	if err != nil {
+		return "", err
+	}
+	if version == "" {
+		return "", fmt.Errorf("version cannot be empty")
+	}
+
+	info, err := c.GetPluginInfo(ctx, slug, WithQueryFields(QueryFields{"versions": true}))
+	if err != nil {
+		return "", err
+	}
+
+	downloadURL, ok := info.Versions[version]
+	if !ok || downloadURL == "" {
+		downloadURL = c.DownloadURL(slug, version)
+	}
+
+	return downloadURL, nil
+}
+
+// ListVersions returns the released versions of a plugin, newest first as
+// ordered by Version.Compare, followed by any entries ParseVersion rejects
+// in string order. The development "trunk" entry is excluded. When the API
+// doesn't list any versions, only the current version is returned.
+func (c *Client) ListVersions(ctx context.Context, slug string) ([]string, error) {
+	info, err := c.GetPluginInfo(ctx, slug, WithQueryFields(QueryFields{"versions": true}))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		parsed []Version
+		other  []string
+	)
+	for version := range info.Versions {
+		if version == "trunk" {
+			continue
+		}
+		if v, err := ParseVersion(version); err == nil {
+			parsed = append(parsed, v)
+		} else {
+			other = append(other, version)
+		}
+	}
+	if len(parsed) == 0 && len(other) == 0 {
+		if info.Version == "" {
+			return nil, nil
+		}
+		return []string{info.Version}, nil
+	}
+
+	slices.SortFunc(parsed, func(a, b Version) int {
+		return cmp.Or(b.Compare(a), strings.Compare(a.String(), b.String()))
+	})
+	slices.Sort(other)
+
+	versions := make([]string, 0, len(parsed)+len(other))
+	for _, v := range parsed {
+		versions = append(versions, v.String())
+	}
+	return append(versions, other...), nil
+}
+
+// DownloadTrunk downloads the development build of a plugin, built from its
+// SVN trunk. Unlike the tagged releases fetched by DownloadPluginVersion,
+// trunk holds whatever the author last committed and may be unreleased or
+// broken. Note that DownloadURL with an empty version is not trunk: it
+// resolves to the latest stable release. The trunk URL is taken from the
+// "trunk" entry of the plugin's Versions map. slug is normalized with
+// NormalizeSlug.
+func (c *Client) DownloadTrunk(ctx context.Context, slug string) ([]byte, error) {
+	slug, err := NormalizeSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := c.GetPluginInfo(ctx, slug, WithQueryFields(QueryFields{"versions": true}))
+	if err != nil {
+		return nil, err
+	}
+
+	trunkURL := info.Versions["trunk"]
+	if trunkURL == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTrunkUnavailable, slug)
+	}
+
+	data, err := c.DownloadPlugin(ctx, trunkURL)
+	if IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s: %w", ErrTrunkUnavailable, slug, err)
+	}
+	return data, err
+}
+
+// FetchAndInspect downloads the latest release of a plugin and parses the
+// header of its main file, returning it along with the API metadata so the
+// two can be compared. The main file is the first PHP file directly inside
+// the slug directory of the archive that declares a plugin header, in name
+// order.
+func (c *Client) FetchAndInspect(ctx context.Context, slug string) (*PluginInfo, *PluginHeader, error) {
+	slug, err := NormalizeSlug(slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := c.GetPluginInfo(ctx, slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	downloadURL := info.DownloadLink
+	if downloadURL == "" {
+		downloadURL = c.DownloadURL(slug, "")
+	}
+	data, err := c.DownloadPlugin(ctx, downloadURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidPluginZip, err)
+	}
+	header, err := readMainFileHeader(zr, slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return info, header, nil
+}
+
+// readMainFileHeader returns the header of the first PHP file in dir that
+// declares a plugin header
+func readMainFileHeader(fsys fs.FS, dir string) (*PluginHeader, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: no top-level directory %q", ErrInvalidPluginZip, dir)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".php" {
+			continue
+		}
+
+		f, err := fsys.Open(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", entry.Name(), err)
+		}
+		header, err := ReadPluginHeader(f)
+		f.Close()
+		if errors.Is(err, ErrNoPluginHeader) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return header, nil
+	}
+
+	return nil, fmt.Errorf("%w: no plugin header found in %s/*.php", ErrInvalidPluginZip, dir)
+}
@@ -0,0 +1,287 @@
+package wordpress_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_DownloadURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		slug    string
+		version string
+		want    string
+	}{
+		{
+			name:    "tagged version",
+			slug:    "akismet",
+			version: "5.5",
+			want:    "https://downloads.wordpress.org/plugin/akismet.5.5.zip",
+		},
+		{
+			name:    "latest",
+			slug:    "akismet",
+			version: "",
+			want:    "https://downloads.wordpress.org/plugin/akismet.zip",
+		},
+		{
+			name:    "slug with dots",
+			slug:    "wp-super-cache.legacy",
+			version: "1.2.0-beta1",
+			want:    "https://downloads.wordpress.org/plugin/wp-super-cache.legacy.1.2.0-beta1.zip",
+		},
+	}
+
+	client := wordpress.NewClient()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := client.DownloadURL(tt.slug, tt.version); got != tt.want {
+				t.Errorf("DownloadURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_DownloadURL_CustomHost(t *testing.T) {
+	client := wordpress.NewClient(wordpress.WithDownloadsBaseURL("http://mirror.example.com/plugin"))
+
+	want := "http://mirror.example.com/plugin/akismet.5.5.zip"
+	if got := client.DownloadURL("akismet", "5.5"); got != want {
+		t.Errorf("DownloadURL() = %s, want %s", got, want)
+	}
+}
+
+func TestClient_Download(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("PK\x03\x04akismet"))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient()
+
+	data, err := client.Download(context.Background(), wordpress.PluginInfo{Slug: "akismet", DownloadLink: server.URL + "/akismet.zip"})
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if string(data) != "PK\x03\x04akismet" {
+		t.Errorf("Download() = %q", data)
+	}
+
+	_, err = client.Download(context.Background(), wordpress.PluginInfo{Slug: "akismet"})
+	if err == nil || !strings.Contains(err.Error(), "akismet") {
+		t.Errorf("Expected an error naming the slug, got %v", err)
+	}
+}
+
+func TestClient_DownloadPluginVersion(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/info/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("request[fields][versions]") != "1" {
+			t.Error("Expected versions field to be requested")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{
+			Slug: "akismet",
+			Versions: wordpress.Versions{
+				"5.4": server.URL + "/listed/akismet.5.4.zip",
+			},
+		})
+	})
+	mux.HandleFunc("/listed/akismet.5.4.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("PK\x03\x04listed"))
+	})
+	mux.HandleFunc("/plugin/akismet.5.0.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("PK\x03\x04fallback"))
+	})
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL+"/info/"),
+		wordpress.WithDownloadsBaseURL(server.URL+"/plugin/"),
+	)
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "5.4", want: "PK\x03\x04listed"},
+		{version: "5.0", want: "PK\x03\x04fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			data, err := client.DownloadPluginVersion(context.Background(), "akismet", tt.version)
+			if err != nil {
+				t.Fatalf("DownloadPluginVersion() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("DownloadPluginVersion() = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ListVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("request[slug]") {
+		case "akismet":
+			w.Write([]byte(`{
+				"slug": "akismet",
+				"version": "2.0",
+				"versions": {
+					"1.0": "https://downloads.wordpress.org/plugin/akismet.1.0.zip",
+					"1.10": "https://downloads.wordpress.org/plugin/akismet.1.10.zip",
+					"1.2": "https://downloads.wordpress.org/plugin/akismet.1.2.zip",
+					"1.0.1": "https://downloads.wordpress.org/plugin/akismet.1.0.1.zip",
+					"2.0-beta1": "https://downloads.wordpress.org/plugin/akismet.2.0-beta1.zip",
+					"2.0-RC1": "https://downloads.wordpress.org/plugin/akismet.2.0-RC1.zip",
+					"2.0": "https://downloads.wordpress.org/plugin/akismet.2.0.zip",
+					"trunk": "https://downloads.wordpress.org/plugin/akismet.zip"
+				}
+			}`))
+		default:
+			w.Write([]byte(`{"slug": "hello-dolly", "version": "1.7.2", "versions": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	tests := []struct {
+		slug string
+		want []string
+	}{
+		{
+			slug: "akismet",
+			want: []string{"2.0", "2.0-RC1", "2.0-beta1", "1.10", "1.2", "1.0.1", "1.0"},
+		},
+		{
+			slug: "hello-dolly",
+			want: []string{"1.7.2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			got, err := client.ListVersions(context.Background(), tt.slug)
+			if err != nil {
+				t.Fatalf("ListVersions() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_DownloadTrunk(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/info/", func(w http.ResponseWriter, r *http.Request) {
+		info := wordpress.PluginInfo{Slug: r.URL.Query().Get("request[slug]")}
+		switch info.Slug {
+		case "akismet":
+			info.Versions = wordpress.Versions{
+				"5.5":   server.URL + "/plugin/akismet.5.5.zip",
+				"trunk": server.URL + "/plugin/akismet.zip",
+			}
+		case "gone":
+			info.Versions = wordpress.Versions{"trunk": server.URL + "/plugin/gone.zip"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(info)
+	})
+	mux.HandleFunc("/plugin/akismet.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("PK\x03\x04trunk"))
+	})
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL+"/info/"),
+		wordpress.WithDownloadsBaseURL(server.URL+"/plugin/"),
+	)
+
+	data, err := client.DownloadTrunk(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("DownloadTrunk() error = %v", err)
+	}
+	if string(data) != "PK\x03\x04trunk" {
+		t.Errorf("DownloadTrunk() = %q, want the trunk archive", data)
+	}
+
+	for _, slug := range []string{"no-trunk", "gone"} {
+		if _, err := client.DownloadTrunk(context.Background(), slug); !errors.Is(err, wordpress.ErrTrunkUnavailable) {
+			t.Errorf("DownloadTrunk(%s): expected ErrTrunkUnavailable, got %v", slug, err)
+		}
+	}
+
+	for _, slug := range []string{"", "   ", "../x"} {
+		if _, err := client.DownloadTrunk(context.Background(), slug); err == nil || errors.Is(err, wordpress.ErrTrunkUnavailable) {
+			t.Errorf("DownloadTrunk(%q): expected an invalid slug error, got %v", slug, err)
+		}
+	}
+}
+
+func TestClient_FetchAndInspect(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	archives := map[string][]byte{
+		"hello-dolly": buildZip(t, map[string]string{
+			"hello-dolly/readme.txt":        "=== Hello Dolly ===",
+			"hello-dolly/includes/main.php": "<?php\n/**\n * Plugin Name: Nested\n */",
+			"hello-dolly/hello.php":         "<?php\n/**\n * Plugin Name: Hello Dolly\n * Version: 1.7.1\n */",
+		}),
+		"no-header": buildZip(t, map[string]string{
+			"no-header/index.php": "<?php // Silence is golden.",
+		}),
+	}
+
+	mux.HandleFunc("/info/", func(w http.ResponseWriter, r *http.Request) {
+		slug := r.URL.Query().Get("request[slug]")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{
+			Name:    "Hello Dolly",
+			Slug:    slug,
+			Version: "1.7.2",
+		})
+	})
+	mux.HandleFunc("/plugin/", func(w http.ResponseWriter, r *http.Request) {
+		slug := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/plugin/"), ".zip")
+		w.Write(archives[slug])
+	})
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL+"/info/"),
+		wordpress.WithDownloadsBaseURL(server.URL+"/plugin/"),
+	)
+
+	info, header, err := client.FetchAndInspect(context.Background(), "hello-dolly")
+	if err != nil {
+		t.Fatalf("FetchAndInspect() error = %v", err)
+	}
+	if info.Version != "1.7.2" {
+		t.Errorf("Expected API version 1.7.2, got %s", info.Version)
+	}
+	if header.Name != "Hello Dolly" || header.Version != "1.7.1" {
+		t.Errorf("Expected the header of hello.php, got %+v", header)
+	}
+
+	if _, _, err := client.FetchAndInspect(context.Background(), "no-header"); !errors.Is(err, wordpress.ErrInvalidPluginZip) {
+		t.Errorf("Expected ErrInvalidPluginZip, got %v", err)
+	}
+}
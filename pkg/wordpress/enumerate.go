@@ -0,0 +1,163 @@
+package wordpress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// EnumerationState records how far an enumeration of a browse set got, so
+// it can be persisted and resumed with ResumeQuery after a crash. NextPage
+// is 0 once the last page was fetched.
+type EnumerationState struct {
+	Browse   string `json:"browse"`
+	NextPage int    `json:"next_page"`
+}
+
+// Done reports whether the enumeration reached the last page
+func (s EnumerationState) Done() bool {
+	return s.NextPage == 0
+}
+
+// WithPageRetry makes QueryAllPlugins, ResumeQuery and QueryUpdatedSince
+// retry a page up to n times when it fails with a transient error, such as
+// a connection failure or a 5xx or 429 response, instead of ending the
+// enumeration. The client backs off between attempts, starting at one
+// second and doubling up to the WithMaxRetryAfter bound.
+func WithPageRetry(n int) ClientOption {
+	return func(c *Client) {
+		c.pageRetries = n
+	}
+}
+
+// QueryAllPlugins queries the pages of a browse set in order and returns up
+// to limit plugins, or every plugin when limit is 0. Info.Pages of each
+// response decides when to stop, so a short last page or a set smaller
+// than limit ends the enumeration instead of requesting a page past the end.
+// The API caps pagination for some browse sets; when it refuses a page
+// after the first, the plugins collected so far are returned without an
+// error.
+func (c *Client) QueryAllPlugins(ctx context.Context, browse string, perPage, limit int, opts ...QueryOption) ([]PluginInfo, error) {
+	return c.enumerate(ctx, EnumerationState{Browse: browse, NextPage: 1}, perPage, limit, nil, opts)
+}
+
+// ResumeQuery continues an enumeration from state.NextPage and returns the
+// plugins of the remaining pages. After each page, save is called with the
+// updated state; persisting it lets a later ResumeQuery pick up from there.
+// An error from save stops the enumeration. save may be nil.
+func (c *Client) ResumeQuery(ctx context.Context, state EnumerationState, perPage int, save func(EnumerationState) error, opts ...QueryOption) ([]PluginInfo, error) {
+	if state.Done() {
+		return nil, nil
+	}
+	return c.enumerate(ctx, state, perPage, 0, save, opts)
+}
+
+// QueryUpdatedSince returns the plugins updated at or after since. The API
+// can't filter by date, so it walks the BrowseUpdated pages, which list
+// recently updated plugins first, and filters them with
+// FilterUpdatedSince. That order is only approximate: the walk stops after
+// the first page containing a plugin older than since, so a recently
+// updated plugin listed beyond that page is missed. last_updated is always
+// requested, even if opts disable it.
+func (c *Client) QueryUpdatedSince(ctx context.Context, since time.Time, perPage int, opts ...QueryOption) ([]PluginInfo, error) {
+	opts = append(slices.Clone(opts), WithQueryFields(QueryFields{"last_updated": true}))
+
+	var plugins []PluginInfo
+	for page := 1; ; page++ {
+		resp, err := c.queryPage(ctx, BrowseUpdated, perPage, page, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query page %d: %w", page, err)
+		}
+
+		recent := FilterUpdatedSince(resp.Plugins, since)
+		plugins = append(plugins, recent...)
+		if len(recent) < len(resp.Plugins) || page >= resp.Info.Pages {
+			return plugins, nil
+		}
+	}
+}
+
+// enumerate fetches pages from state.NextPage on. The API caps how deep
+// some browse sets can be paginated and answers the first page past the
+// cap with an error, or with an empty page, even though Info.Pages
+// promises more; the enumeration then ends normally with the plugins
+// collected so far and a warning is logged.
+func (c *Client) enumerate(ctx context.Context, state EnumerationState, perPage, limit int, save func(EnumerationState) error, opts []QueryOption) ([]PluginInfo, error) {
+	var plugins []PluginInfo
+	for {
+		page := state.NextPage
+		resp, err := c.queryPage(ctx, state.Browse, perPage, page, opts)
+		switch {
+		case err != nil && page > 1 && isPageCapError(err):
+			c.loggerFor(ctx).WarnContext(ctx, "API stopped paginating, ending enumeration early",
+				slog.String("browse", state.Browse), slog.Int("page", page), slog.Any("error", err))
+			resp = &QueryPluginsResponse{Info: QueryInfo{Page: page, Pages: page}}
+		case err != nil:
+			return nil, fmt.Errorf("failed to query page %d: %w", page, err)
+		case len(resp.Plugins) == 0 && page < resp.Info.Pages:
+			c.loggerFor(ctx).WarnContext(ctx, "API returned an empty page, ending enumeration early",
+				slog.String("browse", state.Browse), slog.Int("page", page), slog.Int("pages", resp.Info.Pages))
+			resp.Info.Pages = page
+		}
+
+		plugins = append(plugins, resp.Plugins...)
+
+		state.NextPage = page + 1
+		if page >= resp.Info.Pages {
+			state.NextPage = 0
+		}
+		if save != nil {
+			if err := save(state); err != nil {
+				return nil, fmt.Errorf("failed to save enumeration state: %w", err)
+			}
+		}
+
+		if limit > 0 && len(plugins) >= limit {
+			return plugins[:limit], nil
+		}
+		if state.Done() {
+			return plugins, nil
+		}
+	}
+}
+
+// queryPage queries one page of an enumeration, retrying transient failures
+// as configured with WithPageRetry
+func (c *Client) queryPage(ctx context.Context, browse string, perPage, page int, opts []QueryOption) (*QueryPluginsResponse, error) {
+	delay := defaultRetryAfter
+	for attempt := 0; ; attempt++ {
+		resp, err := c.QueryPlugins(ctx, browse, perPage, page, opts...)
+		if err == nil || attempt >= c.pageRetries || ctx.Err() != nil || !isTransient(err) {
+			return resp, err
+		}
+
+		c.loggerFor(ctx).DebugContext(ctx, "page failed, retrying",
+			slog.String("browse", browse), slog.Int("page", page), slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay), slog.Any("error", err))
+		if err := c.clock.Sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		delay = min(2*delay, c.maxRetryAfter)
+	}
+}
+
+// isTransient reports whether a request that failed with err may succeed
+// when retried
+func isTransient(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.code == http.StatusTooManyRequests {
+		return true
+	}
+	return shouldFailover(err)
+}
+
+// isPageCapError reports whether err is the API refusing a page rather than
+// a transport or server failure
+func isPageCapError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError
+}
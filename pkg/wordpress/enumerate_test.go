@@ -0,0 +1,284 @@
+package wordpress_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_QueryAllPlugins(t *testing.T) {
+	// Two pages, the last one short; anything past page 2 is an error
+	pages := map[string][]wordpress.PluginInfo{
+		"1": {{Slug: "akismet"}, {Slug: "jetpack"}},
+		"2": {{Slug: "hello-dolly"}},
+	}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("request[page]")
+		requested = append(requested, page)
+
+		plugins, ok := pages[page]
+		if !ok {
+			http.Error(w, `{"error":"Invalid page"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.QueryPluginsResponse{
+			Info:    wordpress.QueryInfo{Page: len(requested), Pages: 2, Results: 3},
+			Plugins: plugins,
+		})
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	tests := []struct {
+		name  string
+		limit int
+		want  []string
+	}{
+		{name: "limit beyond the last page", limit: 250, want: []string{"akismet", "jetpack", "hello-dolly"}},
+		{name: "no limit", limit: 0, want: []string{"akismet", "jetpack", "hello-dolly"}},
+		{name: "limit within the first page", limit: 1, want: []string{"akismet"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+
+			plugins, err := client.QueryAllPlugins(context.Background(), wordpress.BrowsePopular, 2, tt.limit)
+			if err != nil {
+				t.Fatalf("QueryAllPlugins() error = %v (requested pages %v)", err, requested)
+			}
+
+			var slugs []string
+			for _, p := range plugins {
+				slugs = append(slugs, p.Slug)
+			}
+			if !slices.Equal(slugs, tt.want) {
+				t.Errorf("QueryAllPlugins() = %v, want %v", slugs, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ResumeQuery(t *testing.T) {
+	var requested []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("request[page]"))
+		requested = append(requested, page)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.QueryPluginsResponse{
+			Info:    wordpress.QueryInfo{Page: page, Pages: 4, Results: 4},
+			Plugins: []wordpress.PluginInfo{{Slug: "plugin-" + strconv.Itoa(page)}},
+		})
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	var saved []wordpress.EnumerationState
+	save := func(s wordpress.EnumerationState) error {
+		saved = append(saved, s)
+		return nil
+	}
+
+	state := wordpress.EnumerationState{Browse: wordpress.BrowsePopular, NextPage: 3}
+	plugins, err := client.ResumeQuery(context.Background(), state, 1, save)
+	if err != nil {
+		t.Fatalf("ResumeQuery() error = %v", err)
+	}
+
+	if !slices.Equal(requested, []int{3, 4}) {
+		t.Errorf("Expected pages 3 and 4 to be requested, got %v", requested)
+	}
+	if len(plugins) != 2 || plugins[0].Slug != "plugin-3" {
+		t.Errorf("Unexpected plugins: %+v", plugins)
+	}
+
+	want := []wordpress.EnumerationState{
+		{Browse: wordpress.BrowsePopular, NextPage: 4},
+		{Browse: wordpress.BrowsePopular, NextPage: 0},
+	}
+	if !slices.Equal(saved, want) {
+		t.Errorf("Saved states = %+v, want %+v", saved, want)
+	}
+
+	// A finished enumeration has nothing left to fetch
+	requested = nil
+	if _, err := client.ResumeQuery(context.Background(), saved[len(saved)-1], 1, nil); err != nil {
+		t.Fatalf("ResumeQuery() error = %v", err)
+	}
+	if len(requested) != 0 {
+		t.Errorf("Expected no requests for a finished enumeration, got %v", requested)
+	}
+}
+
+func TestClient_QueryUpdatedSince(t *testing.T) {
+	// Pages of (slug, last_updated), newest first; page 2 crosses the cutoff
+	pages := map[string]string{
+		"1": `[{"slug":"a","last_updated":"2024-05-20 1:00pm GMT"},{"slug":"b","last_updated":"2024-05-18 9:30am GMT"}]`,
+		"2": `[{"slug":"c","last_updated":"2024-05-10 2:00pm GMT"},{"slug":"d","last_updated":"2024-04-28 8:00am GMT"}]`,
+		"3": `[{"slug":"e","last_updated":"2024-04-01 8:00am GMT"}]`,
+	}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("request[browse]") != wordpress.BrowseUpdated || q.Get("request[fields][last_updated]") != "1" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		page := q.Get("request[page]")
+		requested = append(requested, page)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"info":{"page":%s,"pages":3,"results":5},"plugins":%s}`, page, pages[page])
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	plugins, err := client.QueryUpdatedSince(context.Background(), since, 2, wordpress.WithQueryFields(wordpress.MinimalFields()))
+	if err != nil {
+		t.Fatalf("QueryUpdatedSince() error = %v", err)
+	}
+
+	var slugs []string
+	for _, p := range plugins {
+		slugs = append(slugs, p.Slug)
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(slugs, want) {
+		t.Errorf("QueryUpdatedSince() = %v, want %v", slugs, want)
+	}
+	if want := []string{"1", "2"}; !slices.Equal(requested, want) {
+		t.Errorf("Requested pages %v, want %v", requested, want)
+	}
+}
+
+func TestClient_QueryAllPlugins_PageCap(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter)
+	}{
+		{
+			name: "error object",
+			handler: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"Invalid page number."}`))
+			},
+		},
+		{
+			name: "false body",
+			handler: func(w http.ResponseWriter) {
+				w.Write([]byte(`false`))
+			},
+		},
+		{
+			name: "empty page",
+			handler: func(w http.ResponseWriter) {
+				json.NewEncoder(w).Encode(wordpress.QueryPluginsResponse{
+					Info: wordpress.QueryInfo{Page: 4, Pages: 50, Results: 100},
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page, _ := strconv.Atoi(r.URL.Query().Get("request[page]"))
+				requested = append(requested, page)
+
+				w.Header().Set("Content-Type", "application/json")
+				if page >= 4 {
+					tt.handler(w)
+					return
+				}
+				// The API claims far more pages than it serves
+				json.NewEncoder(w).Encode(wordpress.QueryPluginsResponse{
+					Info:    wordpress.QueryInfo{Page: page, Pages: 50, Results: 100},
+					Plugins: []wordpress.PluginInfo{{Slug: fmt.Sprintf("plugin-%d-a", page)}, {Slug: fmt.Sprintf("plugin-%d-b", page)}},
+				})
+			}))
+			defer server.Close()
+
+			client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+			plugins, err := client.QueryAllPlugins(context.Background(), wordpress.BrowsePopular, 2, 100)
+			if err != nil {
+				t.Fatalf("QueryAllPlugins() error = %v", err)
+			}
+			if len(plugins) != 6 {
+				t.Errorf("Expected the 6 plugins of pages 1-3, got %d", len(plugins))
+			}
+			if !slices.Equal(requested, []int{1, 2, 3, 4}) {
+				t.Errorf("Requested pages %v, want [1 2 3 4]", requested)
+			}
+		})
+	}
+}
+
+func TestClient_QueryAllPlugins_WithPageRetry(t *testing.T) {
+	var (
+		requested []int
+		failed    bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("request[page]"))
+		requested = append(requested, page)
+
+		// Page 2 fails once
+		if page == 2 && !failed {
+			failed = true
+			http.Error(w, "upstream timeout", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.QueryPluginsResponse{
+			Info:    wordpress.QueryInfo{Page: page, Pages: 3, Results: 3},
+			Plugins: []wordpress.PluginInfo{{Slug: fmt.Sprintf("plugin-%d", page)}},
+		})
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithPageRetry(2),
+		wordpress.WithClock(clock),
+	)
+
+	plugins, err := client.QueryAllPlugins(context.Background(), wordpress.BrowsePopular, 1, 0)
+	if err != nil {
+		t.Fatalf("QueryAllPlugins() error = %v", err)
+	}
+	if len(plugins) != 3 {
+		t.Errorf("Expected 3 plugins, got %d", len(plugins))
+	}
+	if !slices.Equal(requested, []int{1, 2, 2, 3}) {
+		t.Errorf("Requested pages %v, want [1 2 2 3]", requested)
+	}
+	if !slices.Equal(clock.sleeps, []time.Duration{time.Second}) {
+		t.Errorf("Expected one backoff of 1s, slept %v", clock.sleeps)
+	}
+
+	// Without retries the failure ends the enumeration
+	requested, failed = nil, false
+	client = wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+	if _, err := client.QueryAllPlugins(context.Background(), wordpress.BrowsePopular, 1, 0); err == nil {
+		t.Error("Expected the failed page to end the enumeration without WithPageRetry")
+	}
+}
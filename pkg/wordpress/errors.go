@@ -0,0 +1,112 @@
+package wordpress
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Failures fall into three classes that callers can tell apart with
+// errors.Is and errors.As, e.g. to retry only failed requests:
+//
+//   - ErrRequestFailed: no complete response, the server couldn't be
+//     reached or the connection broke
+//   - ErrUnexpectedStatus or *APIError: the server responded with an error
+//   - ErrDecodeFailed: the response couldn't be decoded
+var (
+	// ErrRequestFailed is wrapped by errors from requests that didn't get a
+	// complete response
+	ErrRequestFailed = errors.New("failed to execute request")
+	// ErrUnexpectedStatus matches errors for responses with an unexpected
+	// HTTP status code that carry no API error message
+	ErrUnexpectedStatus = errors.New("unexpected status code")
+	// ErrDecodeFailed is wrapped by errors from responses that couldn't be
+	// decoded
+	ErrDecodeFailed = errors.New("failed to decode response")
+)
+
+// maxErrorBodySize bounds how much of a non-200 response is read looking
+// for an API error message
+const maxErrorBodySize = 64 << 10
+
+// APIError is returned when the WordPress.org API reports an error in the
+// response body, either as {"error": "..."} or as a bare false. The API
+// often does so with status 200.
+type APIError struct {
+	StatusCode int
+	// Message is the API's error message, empty when the body was false
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API returned no result (status %d)", e.StatusCode)
+	}
+	return fmt.Sprintf("API error: %s (status %d)", e.Message, e.StatusCode)
+}
+
+// IsNotFound reports whether err means the requested plugin doesn't exist
+// on WordPress.org
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusNotFound ||
+			apiErr.Message == "" ||
+			strings.Contains(strings.ToLower(apiErr.Message), "not found")
+	}
+
+	var statusErr *statusError
+	return errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound
+}
+
+// parseAPIError returns an *APIError if data is one of the API's error
+// shapes, or nil otherwise
+func parseAPIError(statusCode int, data []byte) *APIError {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("false")) || bytes.Equal(data, []byte("null")) {
+		return &APIError{StatusCode: statusCode}
+	}
+	if !bytes.HasPrefix(data, []byte("{")) {
+		return nil
+	}
+
+	var body struct {
+		Error *string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil || body.Error == nil {
+		return nil
+	}
+
+	return &APIError{StatusCode: statusCode, Message: *body.Error}
+}
+
+// responseError returns the error for a non-200 response: an *APIError if
+// the body carries the API's error message, a *statusError otherwise
+func responseError(resp *http.Response) error {
+	if body, err := decodedBody(resp); err == nil {
+		defer body.Close()
+		data, _ := io.ReadAll(io.LimitReader(body, maxErrorBodySize))
+		if apiErr := parseAPIError(resp.StatusCode, data); apiErr != nil {
+			return apiErr
+		}
+	}
+
+	return &statusError{code: resp.StatusCode}
+}
+
+// statusError is returned for a response with an unexpected status code
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%v: %d", ErrUnexpectedStatus, e.code)
+}
+
+func (e *statusError) Is(target error) bool {
+	return target == ErrUnexpectedStatus
+}
@@ -0,0 +1,144 @@
+package wordpress_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_GetPluginInfo_APIError(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantAPIError bool
+		wantNotFound bool
+	}{
+		{
+			name:         "false body",
+			status:       http.StatusOK,
+			body:         "false",
+			wantAPIError: true,
+			wantNotFound: true,
+		},
+		{
+			name:         "error object",
+			status:       http.StatusOK,
+			body:         `{"error":"Plugin not found."}`,
+			wantAPIError: true,
+			wantNotFound: true,
+		},
+		{
+			name:         "error object with 404",
+			status:       http.StatusNotFound,
+			body:         `{"error":"Plugin not found."}`,
+			wantAPIError: true,
+			wantNotFound: true,
+		},
+		{
+			name:         "other API error",
+			status:       http.StatusOK,
+			body:         `{"error":"Invalid request."}`,
+			wantAPIError: true,
+			wantNotFound: false,
+		},
+		{
+			name:         "plain 404",
+			status:       http.StatusNotFound,
+			body:         "<html>Not Found</html>",
+			wantAPIError: false,
+			wantNotFound: true,
+		},
+		{
+			name:         "server error",
+			status:       http.StatusInternalServerError,
+			body:         "oops",
+			wantAPIError: false,
+			wantNotFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+			info, err := client.GetPluginInfo(context.Background(), "does-not-exist")
+			if err == nil {
+				t.Fatalf("Expected error, got %+v", info)
+			}
+
+			var apiErr *wordpress.APIError
+			if got := errors.As(err, &apiErr); got != tt.wantAPIError {
+				t.Errorf("errors.As(APIError) = %v, want %v (err = %v)", got, tt.wantAPIError, err)
+			}
+			if got := wordpress.IsNotFound(err); got != tt.wantNotFound {
+				t.Errorf("IsNotFound() = %v, want %v (err = %v)", got, tt.wantNotFound, err)
+			}
+		})
+	}
+}
+
+func TestClient_ErrorClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    error
+	}{
+		{
+			name: "malformed JSON",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"name": "Akismet`))
+			},
+			want: wordpress.ErrDecodeFailed,
+		},
+		{
+			name: "unexpected status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "oops", http.StatusInternalServerError)
+			},
+			want: wordpress.ErrUnexpectedStatus,
+		},
+		{
+			name: "connection dropped",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err == nil {
+					conn.Close()
+				}
+			},
+			want: wordpress.ErrRequestFailed,
+		},
+	}
+
+	classes := []error{wordpress.ErrRequestFailed, wordpress.ErrUnexpectedStatus, wordpress.ErrDecodeFailed}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+			_, err := client.GetPluginInfo(context.Background(), "akismet")
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+
+			for _, class := range classes {
+				if got := errors.Is(err, class); got != (class == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, class, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package wordpress
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// estimateConcurrency is how many HEAD requests EstimateQuerySize runs at
+// once
+const estimateConcurrency = 8
+
+// SizeEstimate is the aggregate download size of a set of plugins
+type SizeEstimate struct {
+	// Plugins is the number of plugins in the set
+	Plugins int
+	// Bytes is the total size of the downloads whose size is known
+	Bytes int64
+	// Unknown is the number of plugins whose size couldn't be determined,
+	// e.g. because the server doesn't report Content-Length; they aren't
+	// included in Bytes
+	Unknown int
+}
+
+// EstimateQuerySize returns the total download size of the top count
+// plugins of a browse set, or of the whole set when count is 0, without
+// downloading them. The plugins are listed with QueryAllPlugins and their
+// downloads sized with concurrent HeadDownload requests; downloads whose
+// size can't be determined are counted in Unknown rather than failing the
+// estimate.
+func (c *Client) EstimateQuerySize(ctx context.Context, browse string, count int) (SizeEstimate, error) {
+	perPage := 100
+	if count > 0 {
+		perPage = min(count, perPage)
+	}
+
+	plugins, err := c.QueryAllPlugins(ctx, browse, perPage, count)
+	if err != nil {
+		return SizeEstimate{}, fmt.Errorf("failed to query plugins: %w", err)
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		est   = SizeEstimate{Plugins: len(plugins)}
+		links = make(chan string)
+	)
+
+	for range min(estimateConcurrency, len(plugins)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range links {
+				size := int64(-1)
+				if link != "" && ctx.Err() == nil {
+					if n, err := c.HeadDownload(ctx, link); err == nil {
+						size = n
+					}
+				}
+
+				mu.Lock()
+				if size < 0 {
+					est.Unknown++
+				} else {
+					est.Bytes += size
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, p := range plugins {
+		links <- p.DownloadLink
+	}
+	close(links)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return SizeEstimate{}, err
+	}
+	return est, nil
+}
@@ -0,0 +1,61 @@
+package wordpress_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_EstimateQuerySize(t *testing.T) {
+	sizes := map[string]int64{"akismet": 100, "jetpack": 250, "hello-dolly": 50}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/info/", func(w http.ResponseWriter, r *http.Request) {
+		var plugins []wordpress.PluginInfo
+		for _, slug := range []string{"akismet", "jetpack", "hello-dolly", "no-length", "gone"} {
+			plugins = append(plugins, wordpress.PluginInfo{Slug: slug, DownloadLink: server.URL + "/plugin/" + slug + ".zip"})
+		}
+		plugins = append(plugins, wordpress.PluginInfo{Slug: "no-link"})
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.QueryPluginsResponse{
+			Info:    wordpress.QueryInfo{Page: 1, Pages: 1, Results: len(plugins)},
+			Plugins: plugins,
+		})
+	})
+	mux.HandleFunc("/plugin/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected a HEAD request, got %s", r.Method)
+		}
+		slug := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/plugin/"), ".zip")
+		switch slug {
+		case "no-length":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case "gone":
+			http.NotFound(w, r)
+		default:
+			w.Header().Set("Content-Length", strconv.FormatInt(sizes[slug], 10))
+		}
+	})
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL + "/info/"))
+
+	got, err := client.EstimateQuerySize(context.Background(), wordpress.BrowsePopular, 10)
+	if err != nil {
+		t.Fatalf("EstimateQuerySize() error = %v", err)
+	}
+
+	want := wordpress.SizeEstimate{Plugins: 6, Bytes: 400, Unknown: 3}
+	if got != want {
+		t.Errorf("EstimateQuerySize() = %+v, want %+v", got, want)
+	}
+}
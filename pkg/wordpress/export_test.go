@@ -0,0 +1,28 @@
+package wordpress
+
+import (
+	"net/http"
+	"time"
+)
+
+// ParseRetryAfter parses a Retry-After header value relative to the current
+// time
+func ParseRetryAfter(h string) (time.Duration, bool) { return parseRetryAfter(h, time.Now()) }
+
+// Transport returns the transport of the client's HTTP client
+func (c *Client) Transport() http.RoundTripper { return c.httpClient.Transport }
+
+// ResponseCache exposes the client's response cache to tests
+type ResponseCache = responseCache
+
+func NewResponseCache(ttl time.Duration, maxEntries int, now func() time.Time) *ResponseCache {
+	rc := newResponseCache(ttl, maxEntries)
+	rc.now = now
+	return rc
+}
+
+func (rc *ResponseCache) Get(key string) ([]byte, bool) { return rc.get(key) }
+
+func (rc *ResponseCache) Add(key string, data []byte) { rc.add(key, data) }
+
+func (rc *ResponseCache) Stats() CacheStats { return rc.stats() }
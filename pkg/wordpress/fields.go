@@ -0,0 +1,81 @@
+package wordpress
+
+import (
+	"net/url"
+)
+
+// QueryOption customizes the parameters of a single API call
+type QueryOption func(params url.Values)
+
+// WithExtraParams adds arbitrary parameters to a single call, e.g. a new
+// request[...] parameter the library doesn't support yet. Parameters the
+// method sets itself, such as action, the slug or the pagination, always
+// take precedence.
+func WithExtraParams(extra map[string]string) QueryOption {
+	return func(params url.Values) {
+		for key, value := range extra {
+			params.Set(key, value)
+		}
+	}
+}
+
+// QueryFields toggles optional fields in API responses. Fields set to false
+// are omitted from the response, fields set to true are included even when
+// the API would omit them by default. Fields not listed keep the API default.
+type QueryFields map[string]bool
+
+// WithQueryFields sends request[fields][<name>]=0/1 for each entry in fields
+func WithQueryFields(fields QueryFields) QueryOption {
+	return func(params url.Values) {
+		for name, enabled := range fields {
+			value := "0"
+			if enabled {
+				value = "1"
+			}
+			params.Set("request[fields]["+name+"]", value)
+		}
+	}
+}
+
+// optionalFields lists the optional per-plugin fields the plugins API knows
+// about. name, slug, version and download_link are always returned.
+var optionalFields = []string{
+	"active_installs",
+	"added",
+	"author",
+	"author_profile",
+	"banners",
+	"compatibility",
+	"contributors",
+	"description",
+	"donate_link",
+	"downloaded",
+	"homepage",
+	"icons",
+	"last_updated",
+	"num_ratings",
+	"rating",
+	"ratings",
+	"requires",
+	"requires_php",
+	"requires_plugins",
+	"screenshots",
+	"sections",
+	"short_description",
+	"support_threads",
+	"support_threads_resolved",
+	"tags",
+	"tested",
+	"versions",
+}
+
+// MinimalFields returns QueryFields that disable every optional field, so
+// responses only carry name, slug, version and download_link. Useful for
+// bulk enumeration.
+func MinimalFields() QueryFields {
+	fields := make(QueryFields, len(optionalFields))
+	for _, name := range optionalFields {
+		fields[name] = false
+	}
+	return fields
+}
@@ -0,0 +1,121 @@
+package wordpress_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_QueryPlugins_WithQueryFields(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+
+		// Minimal payload without any of the optional fields
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"info": {"page": 1, "pages": 1, "results": 1},
+			"plugins": [{
+				"name": "Akismet Anti-spam",
+				"slug": "akismet",
+				"version": "5.5",
+				"download_link": "https://downloads.wordpress.org/plugin/akismet.5.5.zip"
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	fields := wordpress.MinimalFields()
+	fields["icons"] = true
+
+	resp, err := client.QueryPlugins(context.Background(), "popular", 1, 1, wordpress.WithQueryFields(fields))
+	if err != nil {
+		t.Fatalf("QueryPlugins() error = %v", err)
+	}
+
+	wantParams := map[string]string{
+		"request[fields][description]": "0",
+		"request[fields][sections]":    "0",
+		"request[fields][icons]":       "1",
+		"request[browse]":              "popular",
+		"action":                       "query_plugins",
+	}
+	for key, want := range wantParams {
+		if got := query[key]; len(got) != 1 || got[0] != want {
+			t.Errorf("Expected %s=%s, got %v", key, want, got)
+		}
+	}
+	for _, key := range []string{"name", "slug", "version", "download_link"} {
+		if _, ok := query["request[fields]["+key+"]"]; ok {
+			t.Errorf("Expected %s not to be toggled", key)
+		}
+	}
+
+	if len(resp.Plugins) != 1 {
+		t.Fatalf("Expected 1 plugin, got %d", len(resp.Plugins))
+	}
+	p := resp.Plugins[0]
+	if p.Slug != "akismet" || p.Version != "5.5" || p.DownloadLink == "" {
+		t.Errorf("Unexpected plugin %+v", p)
+	}
+}
+
+func TestClient_QueryPlugins_DefaultFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for key := range r.URL.Query() {
+			if strings.HasPrefix(key, "request[fields]") {
+				t.Errorf("Unexpected field toggle %s", key)
+			}
+		}
+		w.Write([]byte(`{"info": {"page": 1, "pages": 1, "results": 0}, "plugins": []}`))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	if _, err := client.QueryPlugins(context.Background(), "popular", 1, 1); err != nil {
+		t.Fatalf("QueryPlugins() error = %v", err)
+	}
+}
+
+func TestClient_QueryPlugins_WithExtraParams(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"info": {"page": 1, "pages": 1, "results": 0}, "plugins": []}`))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	extra := wordpress.WithExtraParams(map[string]string{
+		"request[locale]":   "de_DE",
+		"action":            "plugin_information",
+		"request[browse]":   "new",
+		"request[per_page]": "1000",
+		"request[page]":     "9",
+	})
+	if _, err := client.QueryPlugins(context.Background(), wordpress.BrowsePopular, 10, 2, extra); err != nil {
+		t.Fatalf("QueryPlugins() error = %v", err)
+	}
+
+	want := map[string]string{
+		"request[locale]":   "de_DE",
+		"action":            "query_plugins",
+		"request[browse]":   "popular",
+		"request[per_page]": "10",
+		"request[page]":     "2",
+	}
+	for key, value := range want {
+		if got := query[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("%s = %v, want %q", key, got, value)
+		}
+	}
+}
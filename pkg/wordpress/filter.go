@@ -0,0 +1,97 @@
+package wordpress
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FilterPlugins returns the plugins for which pred returns true. The input
+// slice is not modified.
+func FilterPlugins(plugins []PluginInfo, pred func(PluginInfo) bool) []PluginInfo {
+	var result []PluginInfo
+	for _, p := range plugins {
+		if pred(p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// MinActiveInstalls returns a predicate matching plugins with more than n
+// active installs
+func MinActiveInstalls(n int) func(PluginInfo) bool {
+	return func(p PluginInfo) bool {
+		return p.ActiveInstalls > n
+	}
+}
+
+// MinRating returns a predicate matching plugins rated r or higher
+// (ratings are percentages, 0-100)
+func MinRating(r float64) func(PluginInfo) bool {
+	return func(p PluginInfo) bool {
+		return float64(p.Rating) >= r
+	}
+}
+
+// CompatibleWithPHP returns a predicate matching plugins whose RequiresPHP is
+// satisfied by the PHP version ver. Plugins that don't declare a minimum PHP
+// version are considered compatible.
+func CompatibleWithPHP(ver string) func(PluginInfo) bool {
+	return func(p PluginInfo) bool {
+		if p.RequiresPHP == "" {
+			return true
+		}
+		return CompareVersions(ver, p.RequiresPHP.String()) >= 0
+	}
+}
+
+// CompatibleWith reports whether the plugin declares compatibility with
+// WordPress wpVersion, and why. wpVersion must be at least Requires and no
+// newer than Tested; Tested is compared at its own precision, so a plugin
+// tested up to "6.4" covers "6.4.2". Fields the plugin leaves empty are
+// treated as compatible.
+func (p PluginInfo) CompatibleWith(wpVersion string) (bool, string) {
+	requires, tested := p.Requires.String(), p.Tested.String()
+
+	if requires != "" && CompareVersions(wpVersion, requires) < 0 {
+		return false, fmt.Sprintf("requires WordPress %s or later", requires)
+	}
+	if tested != "" && CompareVersions(truncateVersion(wpVersion, tested), tested) > 0 {
+		return false, fmt.Sprintf("only tested up to WordPress %s", tested)
+	}
+
+	switch {
+	case requires == "" && tested == "":
+		return true, "no WordPress requirements declared"
+	case tested == "":
+		return true, fmt.Sprintf("requires WordPress %s, tested version unknown", requires)
+	case requires == "":
+		return true, fmt.Sprintf("tested up to WordPress %s, minimum version unknown", tested)
+	default:
+		return true, fmt.Sprintf("requires WordPress %s, tested up to %s", requires, tested)
+	}
+}
+
+// truncateVersion cuts v to as many dot-separated components as like has
+func truncateVersion(v, like string) string {
+	n := strings.Count(like, ".") + 1
+	parts := strings.SplitN(v, ".", n+1)
+	if len(parts) <= n {
+		return v
+	}
+	return strings.Join(parts[:n], ".")
+}
+
+// UpdatedSince returns a predicate matching plugins last updated at or
+// after since. Plugins without a LastUpdated time don't match.
+func UpdatedSince(since time.Time) func(PluginInfo) bool {
+	return func(p PluginInfo) bool {
+		return !p.LastUpdated.IsZero() && !p.LastUpdated.Before(since)
+	}
+}
+
+// FilterUpdatedSince returns the plugins last updated at or after since
+func FilterUpdatedSince(plugins []PluginInfo, since time.Time) []PluginInfo {
+	return FilterPlugins(plugins, UpdatedSince(since))
+}
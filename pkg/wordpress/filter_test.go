@@ -0,0 +1,126 @@
+package wordpress_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestFilterPlugins(t *testing.T) {
+	plugins := []wordpress.PluginInfo{
+		{Slug: "big", ActiveInstalls: 5000000, Rating: 92, RequiresPHP: "7.4"},
+		{Slug: "small", ActiveInstalls: 1000, Rating: 98, RequiresPHP: "8.1"},
+		{Slug: "unknown-php", ActiveInstalls: 20000, Rating: 60, RequiresPHP: ""},
+	}
+
+	tests := []struct {
+		name string
+		pred func(wordpress.PluginInfo) bool
+		want []string
+	}{
+		{
+			name: "min active installs",
+			pred: wordpress.MinActiveInstalls(10000),
+			want: []string{"big", "unknown-php"},
+		},
+		{
+			name: "min active installs is exclusive",
+			pred: wordpress.MinActiveInstalls(1000),
+			want: []string{"big", "unknown-php"},
+		},
+		{
+			name: "min rating is inclusive",
+			pred: wordpress.MinRating(92),
+			want: []string{"big", "small"},
+		},
+		{
+			name: "compatible with PHP 8.0",
+			pred: wordpress.CompatibleWithPHP("8.0"),
+			want: []string{"big", "unknown-php"},
+		},
+		{
+			name: "compatible with PHP 8.1",
+			pred: wordpress.CompatibleWithPHP("8.1"),
+			want: []string{"big", "small", "unknown-php"},
+		},
+		{
+			name: "compatible with PHP 7.2",
+			pred: wordpress.CompatibleWithPHP("7.2"),
+			want: []string{"unknown-php"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slugs(wordpress.FilterPlugins(plugins, tt.pred))
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("FilterPlugins() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("FilterPlugins() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterUpdatedSince(t *testing.T) {
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	plugins := []wordpress.PluginInfo{
+		{Slug: "fresh", LastUpdated: wordpress.Timestamp{Time: since.Add(48 * time.Hour)}},
+		{Slug: "exact", LastUpdated: wordpress.Timestamp{Time: since}},
+		{Slug: "stale", LastUpdated: wordpress.Timestamp{Time: since.Add(-time.Hour)}},
+		{Slug: "unknown"},
+	}
+
+	var got []string
+	for _, p := range wordpress.FilterUpdatedSince(plugins, since) {
+		got = append(got, p.Slug)
+	}
+	if want := []string{"fresh", "exact"}; !slices.Equal(got, want) {
+		t.Errorf("FilterUpdatedSince() = %v, want %v", got, want)
+	}
+}
+
+func TestPluginInfo_CompatibleWith(t *testing.T) {
+	tests := []struct {
+		name      string
+		requires  string
+		tested    string
+		wpVersion string
+		want      bool
+	}{
+		{name: "within range", requires: "5.8", tested: "6.4", wpVersion: "6.2", want: true},
+		{name: "exactly the minimum", requires: "6.2", tested: "6.4", wpVersion: "6.2", want: true},
+		{name: "below the minimum", requires: "6.2", tested: "6.4", wpVersion: "6.1.1", want: false},
+		{name: "patch below a patch minimum", requires: "6.2.1", tested: "6.4", wpVersion: "6.2", want: false},
+		{name: "exactly the tested version", requires: "5.8", tested: "6.2", wpVersion: "6.2", want: true},
+		{name: "patch release of the tested version", requires: "5.8", tested: "6.2", wpVersion: "6.2.3", want: true},
+		{name: "newer than tested", requires: "5.8", tested: "6.2", wpVersion: "6.3", want: false},
+		{name: "patch above a patch tested version", requires: "5.8", tested: "6.2.1", wpVersion: "6.2.2", want: false},
+		{name: "unknown minimum", tested: "6.4", wpVersion: "4.0", want: true},
+		{name: "unknown tested version", requires: "5.8", wpVersion: "7.0", want: true},
+		{name: "nothing declared", wpVersion: "6.2", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := wordpress.PluginInfo{
+				Requires: wordpress.FlexibleString(tt.requires),
+				Tested:   wordpress.FlexibleString(tt.tested),
+			}
+
+			got, reason := p.CompatibleWith(tt.wpVersion)
+			if got != tt.want {
+				t.Errorf("CompatibleWith(%q) = %v (%s), want %v", tt.wpVersion, got, reason, tt.want)
+			}
+			if reason == "" {
+				t.Error("Expected a reason")
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package wordpress
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// HeaderReadLimit is the number of bytes WordPress reads from a file when
+// looking for plugin headers (see get_file_data in wp-includes/functions.php)
+const HeaderReadLimit = 8192
+
+// ErrNoPluginHeader is returned when a file has no "Plugin Name" header
+var ErrNoPluginHeader = errors.New("no plugin header found")
+
+// PluginHeader contains the metadata declared in a plugin's main file header
+type PluginHeader struct {
+	Name        string
+	PluginURI   string
+	Version     string
+	Description string
+	Author      string
+	AuthorURI   string
+	TextDomain  string
+	DomainPath  string
+	Network     string
+	RequiresWP  string
+	RequiresPHP string
+	UpdateURI   string
+	// RequiresPlugins is the comma-separated list of plugin slugs this
+	// plugin depends on; see RequiredPlugins
+	RequiresPlugins string
+	// License and LicenseURI are the declared license as written, e.g.
+	// "GPLv2 or later"; see NormalizeLicense for the SPDX identifier
+	License    string
+	LicenseURI string
+}
+
+// headerFields maps header field names to the PluginHeader field they populate
+var headerFields = []struct {
+	name  string
+	field func(h *PluginHeader) *string
+}{
+	{"Plugin Name", func(h *PluginHeader) *string { return &h.Name }},
+	{"Plugin URI", func(h *PluginHeader) *string { return &h.PluginURI }},
+	{"Version", func(h *PluginHeader) *string { return &h.Version }},
+	{"Description", func(h *PluginHeader) *string { return &h.Description }},
+	{"Author", func(h *PluginHeader) *string { return &h.Author }},
+	{"Author URI", func(h *PluginHeader) *string { return &h.AuthorURI }},
+	{"Text Domain", func(h *PluginHeader) *string { return &h.TextDomain }},
+	{"Domain Path", func(h *PluginHeader) *string { return &h.DomainPath }},
+	{"Network", func(h *PluginHeader) *string { return &h.Network }},
+	{"Requires at least", func(h *PluginHeader) *string { return &h.RequiresWP }},
+	{"Requires PHP", func(h *PluginHeader) *string { return &h.RequiresPHP }},
+	{"Update URI", func(h *PluginHeader) *string { return &h.UpdateURI }},
+	{"Requires Plugins", func(h *PluginHeader) *string { return &h.RequiresPlugins }},
+	{"License", func(h *PluginHeader) *string { return &h.License }},
+	{"License URI", func(h *PluginHeader) *string { return &h.LicenseURI }},
+}
+
+// headerPatterns holds one compiled pattern per header field, mirroring the
+// expression used by get_file_data
+var headerPatterns = compileHeaderPatterns()
+
+// headerCommentEnd matches the trailing comment or PHP close tag that
+// _cleanup_header_comment strips from header values
+var headerCommentEnd = regexp.MustCompile(`\s*(?:\*/|\?>).*`)
+
+func compileHeaderPatterns() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(headerFields))
+	for i, f := range headerFields {
+		patterns[i] = compileHeaderPattern(f.name)
+	}
+	return patterns
+}
+
+func compileHeaderPattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?mi)^(?:[ \t]*<\?php)?[ \t/*#@]*` + regexp.QuoteMeta(name) + `:(.*)$`)
+}
+
+// matchHeaderField returns the cleaned value matched by pattern, or "" if
+// the field is absent
+func matchHeaderField(content []byte, pattern *regexp.Regexp) string {
+	m := pattern.FindSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	value := headerCommentEnd.ReplaceAll(m[1], nil)
+	return string(bytes.TrimSpace(value))
+}
+
+// ParseHeaderField returns the value of a single header field from the first
+// HeaderReadLimit bytes of content, or "" if it is absent. It works for any
+// WordPress file header, such as the "Theme Name" in a theme's style.css.
+func ParseHeaderField(content []byte, name string) string {
+	if len(content) > HeaderReadLimit {
+		content = content[:HeaderReadLimit]
+	}
+	return matchHeaderField(content, compileHeaderPattern(name))
+}
+
+// ParseFileHeader extracts header fields from the first HeaderReadLimit bytes
+// of content without requiring a "Plugin Name" header. WordPress parses
+// must-use plugins and dropins this way.
+func ParseFileHeader(content []byte) PluginHeader {
+	if len(content) > HeaderReadLimit {
+		content = content[:HeaderReadLimit]
+	}
+
+	var h PluginHeader
+	for i, pattern := range headerPatterns {
+		*headerFields[i].field(&h) = matchHeaderField(content, pattern)
+	}
+
+	return h
+}
+
+// ParsePluginHeader extracts the plugin header from the first
+// HeaderReadLimit bytes of content. It returns ErrNoPluginHeader if the
+// content does not declare a "Plugin Name".
+func ParsePluginHeader(content []byte) (*PluginHeader, error) {
+	h := ParseFileHeader(content)
+	if h.Name == "" {
+		return nil, ErrNoPluginHeader
+	}
+	return &h, nil
+}
+
+// ReadPluginHeader reads up to HeaderReadLimit bytes from r and parses the
+// plugin header
+func ReadPluginHeader(r io.Reader) (*PluginHeader, error) {
+	content, err := readHeaderBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePluginHeader(content)
+}
+
+// ReadFileHeader reads up to HeaderReadLimit bytes from r and parses the
+// header fields without requiring a "Plugin Name"
+func ReadFileHeader(r io.Reader) (PluginHeader, error) {
+	content, err := readHeaderBytes(r)
+	if err != nil {
+		return PluginHeader{}, err
+	}
+	return ParseFileHeader(content), nil
+}
+
+func readHeaderBytes(r io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, HeaderReadLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,124 @@
+package wordpress_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestParsePluginHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *wordpress.PluginHeader
+		wantErr error
+	}{
+		{
+			name: "standard plugin header",
+			content: `<?php
+/**
+ * Plugin Name: Test Plugin
+ * Plugin URI: https://example.com/test-plugin
+ * Description: A test plugin
+ * Version: 1.2.3
+ * Requires at least: 6.0
+ * Requires PHP: 7.4
+ * Author: John Doe
+ * Author URI: https://example.com
+ * Text Domain: test-plugin
+ */`,
+			want: &wordpress.PluginHeader{
+				Name:        "Test Plugin",
+				PluginURI:   "https://example.com/test-plugin",
+				Description: "A test plugin",
+				Version:     "1.2.3",
+				RequiresWP:  "6.0",
+				RequiresPHP: "7.4",
+				Author:      "John Doe",
+				AuthorURI:   "https://example.com",
+				TextDomain:  "test-plugin",
+			},
+		},
+		{
+			name: "license fields",
+			content: `<?php
+/**
+ * Plugin Name: Licensed Plugin
+ * License: GPLv2 or later
+ * License URI: https://www.gnu.org/licenses/gpl-2.0.html
+ */`,
+			want: &wordpress.PluginHeader{
+				Name:       "Licensed Plugin",
+				License:    "GPLv2 or later",
+				LicenseURI: "https://www.gnu.org/licenses/gpl-2.0.html",
+			},
+		},
+		{
+			name: "minimal header",
+			content: `<?php
+/*
+Plugin Name: Minimal Plugin
+*/`,
+			want: &wordpress.PluginHeader{
+				Name: "Minimal Plugin",
+			},
+		},
+		{
+			name:    "single line comment closed on the same line",
+			content: `<?php /* Plugin Name: Inline */ ?>`,
+			want: &wordpress.PluginHeader{
+				Name: "Inline",
+			},
+		},
+		{
+			name: "no header",
+			content: `<?php
+// Just a regular PHP file
+class MyClass {}`,
+			wantErr: wordpress.ErrNoPluginHeader,
+		},
+		{
+			name: "header beyond 8KB",
+			content: strings.Repeat("// comment\n", 800) + `
+/**
+ * Plugin Name: Too Far
+ */`,
+			wantErr: wordpress.ErrNoPluginHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := wordpress.ParsePluginHeader([]byte(tt.content))
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParsePluginHeader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if *got != *tt.want {
+				t.Errorf("ParsePluginHeader() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFileHeader(t *testing.T) {
+	content := `<?php
+/**
+ * Version: 2.0
+ */`
+
+	got := wordpress.ParseFileHeader([]byte(content))
+	if got.Name != "" {
+		t.Errorf("Expected empty name, got %s", got.Name)
+	}
+	if got.Version != "2.0" {
+		t.Errorf("Expected version 2.0, got %s", got.Version)
+	}
+}
@@ -0,0 +1,63 @@
+package wordpress
+
+import "strings"
+
+// spdxLicenses maps the license spellings commonly found in plugin headers
+// and readmes, lowercased and with runs of whitespace collapsed, to SPDX
+// identifiers
+var spdxLicenses = map[string]string{
+	"gplv2":                       "GPL-2.0-only",
+	"gpl v2":                      "GPL-2.0-only",
+	"gpl2":                        "GPL-2.0-only",
+	"gpl-2.0":                     "GPL-2.0-only",
+	"gpl 2.0":                     "GPL-2.0-only",
+	"gpl-2.0-only":                "GPL-2.0-only",
+	"gplv2 or later":              "GPL-2.0-or-later",
+	"gpl v2 or later":             "GPL-2.0-or-later",
+	"gpl2+":                       "GPL-2.0-or-later",
+	"gplv2+":                      "GPL-2.0-or-later",
+	"gpl-2.0+":                    "GPL-2.0-or-later",
+	"gpl-2.0 or later":            "GPL-2.0-or-later",
+	"gpl-2.0-or-later":            "GPL-2.0-or-later",
+	"gpl version 2 or later":      "GPL-2.0-or-later",
+	"gplv3":                       "GPL-3.0-only",
+	"gpl v3":                      "GPL-3.0-only",
+	"gpl3":                        "GPL-3.0-only",
+	"gpl-3.0":                     "GPL-3.0-only",
+	"gpl 3.0":                     "GPL-3.0-only",
+	"gpl-3.0-only":                "GPL-3.0-only",
+	"gplv3 or later":              "GPL-3.0-or-later",
+	"gpl v3 or later":             "GPL-3.0-or-later",
+	"gpl3+":                       "GPL-3.0-or-later",
+	"gplv3+":                      "GPL-3.0-or-later",
+	"gpl-3.0+":                    "GPL-3.0-or-later",
+	"gpl-3.0 or later":            "GPL-3.0-or-later",
+	"gpl-3.0-or-later":            "GPL-3.0-or-later",
+	"gpl version 3 or later":      "GPL-3.0-or-later",
+	"lgplv2.1":                    "LGPL-2.1-only",
+	"lgpl-2.1":                    "LGPL-2.1-only",
+	"lgplv2.1 or later":           "LGPL-2.1-or-later",
+	"lgplv3":                      "LGPL-3.0-only",
+	"lgpl-3.0":                    "LGPL-3.0-only",
+	"mit":                         "MIT",
+	"mit license":                 "MIT",
+	"apache 2.0":                  "Apache-2.0",
+	"apache-2.0":                  "Apache-2.0",
+	"apache license 2.0":          "Apache-2.0",
+	"apache license, version 2.0": "Apache-2.0",
+	"bsd-2-clause":                "BSD-2-Clause",
+	"bsd-3-clause":                "BSD-3-Clause",
+	"mpl-2.0":                     "MPL-2.0",
+}
+
+// NormalizeLicense returns the SPDX identifier for a declared license such
+// as "GPLv2 or later". Licenses it doesn't recognize are returned as
+// written, trimmed of surrounding whitespace.
+func NormalizeLicense(license string) string {
+	license = strings.TrimSpace(license)
+	key := strings.ToLower(strings.Join(strings.Fields(license), " "))
+	if id, ok := spdxLicenses[key]; ok {
+		return id
+	}
+	return license
+}
@@ -0,0 +1,35 @@
+package wordpress_test
+
+import (
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestNormalizeLicense(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "GPLv2 or later", want: "GPL-2.0-or-later"},
+		{input: "  GPLv2  or  later ", want: "GPL-2.0-or-later"},
+		{input: "GPL-2.0+", want: "GPL-2.0-or-later"},
+		{input: "GPLv2", want: "GPL-2.0-only"},
+		{input: "GPLv3", want: "GPL-3.0-only"},
+		{input: "GPL v3 or later", want: "GPL-3.0-or-later"},
+		{input: "MIT", want: "MIT"},
+		{input: "Apache License, Version 2.0", want: "Apache-2.0"},
+		{input: "GPL-2.0-or-later", want: "GPL-2.0-or-later"},
+		{input: "Proprietary", want: "Proprietary"},
+		{input: " Envato Regular License ", want: "Envato Regular License"},
+		{input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := wordpress.NormalizeLicense(tt.input); got != tt.want {
+				t.Errorf("NormalizeLicense(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
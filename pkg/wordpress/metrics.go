@@ -0,0 +1,60 @@
+package wordpress
+
+import (
+	"io"
+	"sync"
+)
+
+// Metrics receives counters about the requests a Client makes, so callers
+// can export them to Prometheus or any other system without this package
+// depending on it. method is the client method that issued the request,
+// e.g. "QueryPlugins" or "DownloadPluginTo". Implementations must be safe
+// for concurrent use.
+type Metrics interface {
+	// IncRequest is called for every HTTP request sent, including retries
+	IncRequest(method string)
+	// ObserveBytes is called with the number of response body bytes read
+	// once the body is closed
+	ObserveBytes(method string, n int64)
+	// IncError is called for failed requests. status is the HTTP status
+	// code, or 0 when no response was received.
+	IncError(method string, status int)
+}
+
+// NopMetrics is a Metrics that discards everything. It is the default and
+// can be embedded to implement only some of the methods.
+type NopMetrics struct{}
+
+func (NopMetrics) IncRequest(string)          {}
+func (NopMetrics) ObserveBytes(string, int64) {}
+func (NopMetrics) IncError(string, int)       {}
+
+// WithMetrics reports request, byte and error counters to m
+func WithMetrics(m Metrics) ClientOption {
+	return func(c *Client) {
+		if m == nil {
+			m = NopMetrics{}
+		}
+		c.metrics = m
+	}
+}
+
+// countingBody reports the number of bytes read from a response body to
+// the client's metrics when it is closed
+type countingBody struct {
+	io.ReadCloser
+	n       int64
+	observe func(n int64)
+	once    sync.Once
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.once.Do(func() { b.observe(b.n) })
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,84 @@
+package wordpress_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+type fakeMetrics struct {
+	mu       sync.Mutex
+	requests map[string]int
+	bytes    map[string]int64
+	errors   map[int]int
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{
+		requests: make(map[string]int),
+		bytes:    make(map[string]int64),
+		errors:   make(map[int]int),
+	}
+}
+
+func (m *fakeMetrics) IncRequest(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[method]++
+}
+
+func (m *fakeMetrics) ObserveBytes(method string, n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes[method] += n
+}
+
+func (m *fakeMetrics) IncError(method string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[status]++
+}
+
+func TestClient_WithMetrics(t *testing.T) {
+	const body = `{"slug":"akismet","name":"Akismet","version":"5.3"}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("request[slug]") != "akismet" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	metrics := newFakeMetrics()
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL), wordpress.WithMetrics(metrics))
+
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if metrics.requests["GetPluginInfo"] != 1 {
+		t.Errorf("Expected 1 request, got %v", metrics.requests)
+	}
+	if metrics.bytes["GetPluginInfo"] != int64(len(body)) {
+		t.Errorf("Expected %d bytes, got %v", len(body), metrics.bytes)
+	}
+	if len(metrics.errors) != 0 {
+		t.Errorf("Expected no errors, got %v", metrics.errors)
+	}
+
+	if _, err := client.GetPluginInfo(context.Background(), "missing"); err == nil {
+		t.Fatal("Expected an error for a missing plugin")
+	}
+	if metrics.requests["GetPluginInfo"] != 2 {
+		t.Errorf("Expected 2 requests, got %v", metrics.requests)
+	}
+	if metrics.errors[http.StatusNotFound] != 1 {
+		t.Errorf("Expected one 404 error, got %v", metrics.errors)
+	}
+}
@@ -0,0 +1,72 @@
+package wordpress
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const purlPrefix = "pkg:wordpress/"
+
+// PURL returns the package URL of the plugin, pkg:wordpress/<slug>@<version>,
+// as used by SBOM and vulnerability tooling. The version is omitted when it
+// is unknown.
+func (p PluginInfo) PURL() string {
+	return FormatPURL(p.Slug, p.Version)
+}
+
+// FormatPURL returns the package URL for slug at version, percent-encoding
+// both the way the purl specification requires
+func FormatPURL(slug, version string) string {
+	s := purlPrefix + purlEscape(slug)
+	if version != "" {
+		s += "@" + purlEscape(version)
+	}
+	return s
+}
+
+// ParsePURL is the inverse of FormatPURL. Qualifiers and subpaths are
+// ignored; a namespace is rejected since WordPress plugins have none.
+func ParsePURL(s string) (slug, version string, err error) {
+	if len(s) < len(purlPrefix) || !strings.EqualFold(s[:len(purlPrefix)], purlPrefix) {
+		return "", "", fmt.Errorf("invalid purl %q: not a pkg:wordpress package URL", s)
+	}
+	rest := s[len(purlPrefix):]
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		rest = rest[:i]
+	}
+
+	name := rest
+	if i := strings.LastIndexByte(rest, '@'); i >= 0 {
+		name = rest[:i]
+		if version, err = url.PathUnescape(rest[i+1:]); err != nil {
+			return "", "", fmt.Errorf("invalid purl %q: %w", s, err)
+		}
+	}
+	if strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid purl %q: unexpected namespace", s)
+	}
+	if slug, err = url.PathUnescape(name); err != nil {
+		return "", "", fmt.Errorf("invalid purl %q: %w", s, err)
+	}
+	if slug == "" {
+		return "", "", fmt.Errorf("invalid purl %q: missing name", s)
+	}
+
+	return slug, version, nil
+}
+
+// purlEscape percent-encodes every byte outside the purl unreserved set
+// (letters, digits and ".-_~")
+func purlEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte(".-_~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
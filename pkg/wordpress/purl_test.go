@@ -0,0 +1,72 @@
+package wordpress_test
+
+import (
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestPluginInfo_PURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		slug    string
+		version string
+		want    string
+	}{
+		{name: "plain", slug: "akismet", version: "5.3.1", want: "pkg:wordpress/akismet@5.3.1"},
+		{name: "no version", slug: "hello-dolly", want: "pkg:wordpress/hello-dolly"},
+		{name: "underscore and tilde kept", slug: "wp_super~cache", version: "1.0", want: "pkg:wordpress/wp_super~cache@1.0"},
+		{name: "at sign in slug", slug: "foo@bar", version: "2.0", want: "pkg:wordpress/foo%40bar@2.0"},
+		{name: "slash and space", slug: "my plugin/x", version: "1.0 beta", want: "pkg:wordpress/my%20plugin%2Fx@1.0%20beta"},
+		{name: "plus in version", slug: "woo", version: "1.0+build.5", want: "pkg:wordpress/woo@1.0%2Bbuild.5"},
+		{name: "unicode", slug: "café", version: "1", want: "pkg:wordpress/caf%C3%A9@1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wordpress.PluginInfo{Slug: tt.slug, Version: tt.version}.PURL()
+			if got != tt.want {
+				t.Fatalf("PURL() = %q, want %q", got, tt.want)
+			}
+
+			slug, version, err := wordpress.ParsePURL(got)
+			if err != nil {
+				t.Fatalf("ParsePURL(%q) error = %v", got, err)
+			}
+			if slug != tt.slug || version != tt.version {
+				t.Errorf("ParsePURL(%q) = %q, %q, want %q, %q", got, slug, version, tt.slug, tt.version)
+			}
+		})
+	}
+}
+
+func TestParsePURL(t *testing.T) {
+	tests := []struct {
+		input       string
+		wantSlug    string
+		wantVersion string
+		wantErr     bool
+	}{
+		{input: "pkg:wordpress/akismet@5.3.1", wantSlug: "akismet", wantVersion: "5.3.1"},
+		{input: "PKG:WordPress/akismet@5.3.1", wantSlug: "akismet", wantVersion: "5.3.1"},
+		{input: "pkg:wordpress/akismet@5.3.1?arch=any#akismet.php", wantSlug: "akismet", wantVersion: "5.3.1"},
+		{input: "pkg:wordpress/akismet", wantSlug: "akismet"},
+		{input: "pkg:npm/akismet@1.0", wantErr: true},
+		{input: "pkg:wordpress/vendor/akismet@1.0", wantErr: true},
+		{input: "pkg:wordpress/@1.0", wantErr: true},
+		{input: "pkg:wordpress/bad%zz@1.0", wantErr: true},
+		{input: "akismet", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			slug, version, err := wordpress.ParsePURL(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if slug != tt.wantSlug || version != tt.wantVersion {
+				t.Errorf("ParsePURL() = %q, %q, want %q, %q", slug, version, tt.wantSlug, tt.wantVersion)
+			}
+		})
+	}
+}
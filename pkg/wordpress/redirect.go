@@ -0,0 +1,36 @@
+package wordpress
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// defaultMaxRedirects matches the limit of Go's default HTTP client
+const defaultMaxRedirects = 10
+
+// ErrTooManyRedirects is returned when a request, typically a download
+// redirected to a mirror, is redirected more often than allowed
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// WithMaxRedirects sets how many redirects a request may follow, 10 by
+// default; 0 disables redirects. Longer chains fail with
+// ErrTooManyRedirects. When combined with WithHTTPClient, the given client
+// is copied and its CheckRedirect replaced; without this option a custom
+// client's redirect policy is kept.
+func WithMaxRedirects(n int) ClientOption {
+	return func(c *Client) {
+		c.maxRedirects = n
+		c.redirectsSet = true
+	}
+}
+
+// checkRedirect is the CheckRedirect policy of the client's HTTP client
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	// via holds every request made so far, so its length is the number of
+	// redirects including this one
+	if len(via) > c.maxRedirects {
+		return fmt.Errorf("%w: stopped after %d redirects at %s", ErrTooManyRedirects, c.maxRedirects, req.URL)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package wordpress_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_WithMaxRedirects(t *testing.T) {
+	// /redirect/<n> redirects n times before serving the archive
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/redirect/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if n > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/redirect/%d", n-1), http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write([]byte("PK\x03\x04mirror"))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := wordpress.NewClient(
+		wordpress.WithMaxRedirects(2),
+		wordpress.WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+
+	if _, err := client.DownloadPlugin(context.Background(), server.URL+"/redirect/2"); err != nil {
+		t.Fatalf("DownloadPlugin() error = %v", err)
+	}
+	if !strings.Contains(logs.String(), "final_url="+server.URL+"/redirect/0") {
+		t.Errorf("Expected the final URL to be logged, got:\n%s", logs.String())
+	}
+
+	_, err := client.DownloadPlugin(context.Background(), server.URL+"/redirect/3")
+	if !errors.Is(err, wordpress.ErrTooManyRedirects) {
+		t.Errorf("Expected ErrTooManyRedirects, got %v", err)
+	}
+
+	// The caller's client is copied, not modified
+	refuse := func(*http.Request, []*http.Request) error { return errors.New("redirects not allowed") }
+	custom := &http.Client{CheckRedirect: refuse}
+	client = wordpress.NewClient(wordpress.WithHTTPClient(custom), wordpress.WithMaxRedirects(2))
+
+	if _, err := client.DownloadPlugin(context.Background(), server.URL+"/redirect/1"); err != nil {
+		t.Fatalf("DownloadPlugin() error = %v", err)
+	}
+	if _, err := custom.Get(server.URL + "/redirect/1"); err == nil {
+		t.Error("Expected the custom client to keep refusing redirects")
+	}
+}
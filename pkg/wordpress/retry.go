@@ -0,0 +1,59 @@
+package wordpress
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultMaxRetryAfter bounds how long the client honors a Retry-After
+	defaultMaxRetryAfter = time.Minute
+	// defaultRetryAfter is used when a 429 response has no usable Retry-After
+	defaultRetryAfter = time.Second
+	// maxRateLimitRetries is how many times a request is retried after 429
+	maxRateLimitRetries = 3
+)
+
+// parseRetryAfter parses a Retry-After header value, which is either a
+// number of seconds or an HTTP date, relative to now. Dates in the past
+// yield zero.
+func parseRetryAfter(h string, now time.Time) (time.Duration, bool) {
+	h = strings.TrimSpace(h)
+	if h == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(h); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(h)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(t.Sub(now), 0), true
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
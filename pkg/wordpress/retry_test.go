@@ -0,0 +1,229 @@
+package wordpress_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "integer seconds",
+			header: "120",
+			want:   120 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "zero seconds",
+			header: "0",
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "http date in the past",
+			header: "Wed, 21 Oct 2015 07:28:00 GMT",
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "empty",
+			header: "",
+			wantOK: false,
+		},
+		{
+			name:   "negative seconds",
+			header: "-5",
+			wantOK: false,
+		},
+		{
+			name:   "malformed",
+			header: "soon",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := wordpress.ParseRetryAfter(tt.header)
+			if ok != tt.wantOK {
+				t.Fatalf("parseRetryAfter(%q) ok = %v, want %v", tt.header, ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter_FutureDate(t *testing.T) {
+	header := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+
+	got, ok := wordpress.ParseRetryAfter(header)
+	if !ok {
+		t.Fatalf("parseRetryAfter(%q) failed", header)
+	}
+	if got <= 20*time.Second || got > 30*time.Second {
+		t.Errorf("parseRetryAfter(%q) = %v, want about 30s", header, got)
+	}
+}
+
+func TestClient_RetriesAfterTooManyRequests(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithMaxRetryAfter(10*time.Millisecond),
+	)
+
+	start := time.Now()
+	info, err := client.GetPluginInfo(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if info.Slug != "akismet" {
+		t.Errorf("Expected slug akismet, got %s", info.Slug)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Retry-After was not bounded by the max: took %v", elapsed)
+	}
+}
+
+func TestClient_TooManyRequestsGivesUp(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); err == nil {
+		t.Fatal("Expected error after repeated 429 responses")
+	}
+	if calls != 4 {
+		t.Errorf("Expected 4 calls (1 + 3 retries), got %d", calls)
+	}
+}
+
+// fakeClock advances instantly on Sleep and records the durations slept
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > 0 {
+		c.sleeps = append(c.sleeps, d)
+		c.now = c.now.Add(d)
+	}
+	return ctx.Err()
+}
+
+func TestClient_WithClock_RetryAfter(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			// An HTTP date is relative to the clock, not to real time
+			w.Header().Set("Retry-After", clock.Now().Add(30*time.Second).Format(http.TimeFormat))
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 3:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+		}
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithMaxRetryAfter(20*time.Second),
+		wordpress.WithClock(clock),
+	)
+
+	start := time.Now()
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the fake clock to skip the waits, took %v", elapsed)
+	}
+
+	// Retry-After as given, capped by the max, and the default without one
+	want := []time.Duration{2 * time.Second, 20 * time.Second, time.Second}
+	if !slices.Equal(clock.sleeps, want) {
+		t.Errorf("Slept %v, want %v", clock.sleeps, want)
+	}
+}
+
+func TestClient_WithClock_RateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: r.URL.Query().Get("request[slug]")})
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithRateLimit(0.5, 1),
+		wordpress.WithClock(clock),
+	)
+
+	for _, slug := range []string{"akismet", "jetpack", "wordfence"} {
+		if _, err := client.GetPluginInfo(context.Background(), slug); err != nil {
+			t.Fatalf("GetPluginInfo(%s) error = %v", slug, err)
+		}
+	}
+
+	// One request every two seconds; the first uses the burst
+	want := []time.Duration{2 * time.Second, 2 * time.Second}
+	if !slices.Equal(clock.sleeps, want) {
+		t.Errorf("Slept %v, want %v", clock.sleeps, want)
+	}
+}
@@ -0,0 +1,51 @@
+package wordpress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithSaltsURL sets a custom URL for the secret key API (mainly for
+// testing)
+func WithSaltsURL(saltsURL string) ClientOption {
+	return func(c *Client) {
+		c.saltsURL = saltsURL
+	}
+}
+
+// GetSalts returns a freshly generated set of authentication keys and
+// salts for wp-config.php, as the block of PHP define statements served by
+// the WordPress.org secret key API. The body is returned verbatim and is
+// never cached or passed to the response interceptor.
+func (c *Client) GetSalts(ctx context.Context) (string, error) {
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.saltsURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// The response is plain text, so unlike getBody it is neither handed
+	// to the interceptor nor checked for a JSON API error
+	resp, err := c.do("GetSalts", req)
+	if err != nil {
+		return "", c.requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", responseError(resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read response: %w", ErrRequestFailed, err)
+	}
+	return string(data), nil
+}
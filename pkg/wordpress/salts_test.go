@@ -0,0 +1,73 @@
+package wordpress_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+const saltsPayload = `define('AUTH_KEY',         'q]4<x$#4Wv^*H|d!+qz:;E)9f1%~Kf-Z');
+define('SECURE_AUTH_KEY',  'Zk&6Uq8<R]vV1?=l$m$!bW/.f7K,kP9x');
+define('NONCE_SALT',       '#p}Hn.h4A{gS^X-z2S0b@Y5f%W6J)Q8d');
+`
+
+func TestClient_GetSalts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain;charset=utf-8")
+		w.Write([]byte(saltsPayload))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithSaltsURL(server.URL))
+
+	salts, err := client.GetSalts(context.Background())
+	if err != nil {
+		t.Fatalf("GetSalts() error = %v", err)
+	}
+	if salts != saltsPayload {
+		t.Errorf("GetSalts() = %q, want %q", salts, saltsPayload)
+	}
+}
+
+func TestClient_GetSalts_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithSaltsURL(server.URL))
+
+	if _, err := client.GetSalts(context.Background()); !errors.Is(err, wordpress.ErrUnexpectedStatus) {
+		t.Errorf("Expected ErrUnexpectedStatus, got %v", err)
+	}
+}
+
+func TestClient_GetSalts_BypassesInterceptor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(saltsPayload))
+	}))
+	defer server.Close()
+
+	var intercepted []string
+	client := wordpress.NewClient(
+		wordpress.WithSaltsURL(server.URL),
+		wordpress.WithResponseInterceptor(func(method string, body []byte) {
+			intercepted = append(intercepted, method)
+		}),
+	)
+
+	salts, err := client.GetSalts(context.Background())
+	if err != nil {
+		t.Fatalf("GetSalts() error = %v", err)
+	}
+	if salts != saltsPayload {
+		t.Errorf("GetSalts() = %q, want %q", salts, saltsPayload)
+	}
+	if len(intercepted) != 0 {
+		t.Errorf("interceptor saw %v, want no calls", intercepted)
+	}
+}
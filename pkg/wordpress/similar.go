@@ -0,0 +1,54 @@
+package wordpress
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxSimilar is the largest limit Similar accepts: the API serves at most
+// 100 plugins per page, and one of them may be the plugin itself
+const maxSimilar = 99
+
+// Similar recommends up to limit plugins sharing the most specific tag of
+// slug, the one with the fewest plugins, ranked by active installs. Every
+// tag of the plugin is queried once to find it. A plugin without tags, or
+// whose tags no other plugin has, gets an empty slice. limit must be
+// between 1 and 99.
+func (c *Client) Similar(ctx context.Context, slug string, limit int) ([]PluginInfo, error) {
+	if limit <= 0 || limit > maxSimilar {
+		return nil, fmt.Errorf("limit must be between 1 and %d, got %d", maxSimilar, limit)
+	}
+
+	info, err := c.GetPluginInfo(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		similar []PluginInfo
+		results int
+	)
+	for _, tag := range info.TagSlugs() {
+		// One extra result makes up for the plugin itself being listed
+		resp, err := c.QueryPluginsByTag(ctx, tag, limit+1, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query tag %s: %w", tag, err)
+		}
+
+		others := FilterPlugins(resp.Plugins, func(p PluginInfo) bool {
+			return p.Slug != info.Slug
+		})
+		if len(others) == 0 {
+			continue
+		}
+		if similar == nil || resp.Info.Results < results {
+			similar, results = others, resp.Info.Results
+		}
+	}
+
+	if similar == nil {
+		return []PluginInfo{}, nil
+	}
+	SortPlugins(similar, SortByActiveInstalls, true)
+	return similar[:min(limit, len(similar))], nil
+}
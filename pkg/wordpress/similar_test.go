@@ -0,0 +1,100 @@
+package wordpress_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_Similar(t *testing.T) {
+	tagged := map[string]wordpress.QueryPluginsResponse{
+		"contact-form": {
+			Info: wordpress.QueryInfo{Page: 1, Pages: 1, Results: 3},
+			Plugins: []wordpress.PluginInfo{
+				{Slug: "contact-form-7", ActiveInstalls: 10000000},
+				{Slug: "ninja-forms", ActiveInstalls: 800000},
+				{Slug: "wpforms-lite", ActiveInstalls: 6000000},
+			},
+		},
+		"email": {
+			Info: wordpress.QueryInfo{Page: 1, Pages: 50, Results: 5000},
+			Plugins: []wordpress.PluginInfo{
+				{Slug: "wp-mail-smtp", ActiveInstalls: 3000000},
+			},
+		},
+		"lonely": {
+			Info:    wordpress.QueryInfo{Page: 1, Pages: 1, Results: 1},
+			Plugins: []wordpress.PluginInfo{{Slug: "lonely-plugin"}},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+
+		if query.Get("action") == "query_plugins" {
+			json.NewEncoder(w).Encode(tagged[query.Get("request[tag]")])
+			return
+		}
+
+		info := wordpress.PluginInfo{Slug: query.Get("request[slug]")}
+		switch info.Slug {
+		case "contact-form-7":
+			info.Tags = wordpress.Tags{"contact-form": "contact form", "email": "email"}
+		case "lonely-plugin":
+			info.Tags = wordpress.Tags{"lonely": "lonely"}
+		}
+		json.NewEncoder(w).Encode(info)
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	tests := []struct {
+		name  string
+		slug  string
+		limit int
+		want  []string
+	}{
+		{name: "most specific tag", slug: "contact-form-7", limit: 5, want: []string{"wpforms-lite", "ninja-forms"}},
+		{name: "limit", slug: "contact-form-7", limit: 1, want: []string{"wpforms-lite"}},
+		{name: "no tags", slug: "untagged", limit: 5, want: []string{}},
+		{name: "no other plugin", slug: "lonely-plugin", limit: 5, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			similar, err := client.Similar(context.Background(), tt.slug, tt.limit)
+			if err != nil {
+				t.Fatalf("Similar() error = %v", err)
+			}
+			if similar == nil {
+				t.Fatal("Expected an empty slice, got nil")
+			}
+
+			got := []string{}
+			for _, p := range similar {
+				got = append(got, p.Slug)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Similar() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := client.Similar(context.Background(), "contact-form-7", 0); err == nil {
+		t.Error("Expected an error for a zero limit")
+	}
+	// limit+1 plugins are requested per tag, which must fit in one page
+	if _, err := client.Similar(context.Background(), "contact-form-7", 99); err != nil {
+		t.Errorf("Similar() error = %v for a limit of 99", err)
+	}
+	if _, err := client.Similar(context.Background(), "contact-form-7", 100); err == nil {
+		t.Error("Expected an error for a limit of 100")
+	}
+}
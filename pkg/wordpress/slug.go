@@ -0,0 +1,63 @@
+package wordpress
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NormalizeSlug extracts the canonical plugin slug from user input such as
+// "Akismet", "akismet/" or "https://wordpress.org/plugins/akismet/". URLs
+// must point into a plugins directory. The slug is lowercased and may only
+// contain letters, digits, hyphens and underscores.
+func NormalizeSlug(input string) (string, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return "", fmt.Errorf("slug cannot be empty")
+	}
+
+	if strings.Contains(s, "://") || strings.HasPrefix(s, "wordpress.org/") {
+		if !strings.Contains(s, "://") {
+			s = "https://" + s
+		}
+		u, err := url.Parse(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid slug %q: %w", input, err)
+		}
+		s = slugFromPath(u.Path)
+		if s == "" {
+			return "", fmt.Errorf("invalid slug %q: not a plugin URL", input)
+		}
+	}
+
+	s = strings.ToLower(strings.Trim(s, "/"))
+	if !isValidSlug(s) {
+		return "", fmt.Errorf("invalid slug %q", input)
+	}
+
+	return s, nil
+}
+
+// slugFromPath returns the path segment following "plugins", e.g. akismet
+// for /plugins/akismet/ or /wp-content/plugins/akismet/akismet.php
+func slugFromPath(p string) string {
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	for i, segment := range segments[:len(segments)-1] {
+		if segment == "plugins" {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+func isValidSlug(s string) bool {
+	if s == "" || s[0] == '-' || s[0] == '_' {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
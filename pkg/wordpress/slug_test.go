@@ -0,0 +1,45 @@
+package wordpress_test
+
+import (
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestNormalizeSlug(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "akismet", want: "akismet"},
+		{input: "  Akismet ", want: "akismet"},
+		{input: "akismet/", want: "akismet"},
+		{input: "contact-form-7", want: "contact-form-7"},
+		{input: "https://wordpress.org/plugins/Akismet/", want: "akismet"},
+		{input: "https://wordpress.org/plugins/akismet/#developers", want: "akismet"},
+		{input: "http://de.wordpress.org/plugins/akismet", want: "akismet"},
+		{input: "wordpress.org/plugins/hello-dolly/", want: "hello-dolly"},
+		{input: "https://example.com/wp-content/plugins/akismet/akismet.php", want: "akismet"},
+		{input: "", wantErr: true},
+		{input: "/", wantErr: true},
+		{input: "akismet/akismet.php", wantErr: true},
+		{input: "../etc/passwd", wantErr: true},
+		{input: "hello dolly", wantErr: true},
+		{input: "-akismet", wantErr: true},
+		{input: "https://wordpress.org/themes/twentytwenty/", wantErr: true},
+		{input: "https://wordpress.org/plugins/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := wordpress.NormalizeSlug(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeSlug(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeSlug(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
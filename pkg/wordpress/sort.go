@@ -0,0 +1,54 @@
+package wordpress
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// SortKey selects the PluginInfo field SortPlugins orders by
+type SortKey int
+
+const (
+	SortByName SortKey = iota
+	SortByActiveInstalls
+	SortByDownloaded
+	SortByRating
+	SortByNumRatings
+	SortByLastUpdated
+)
+
+// SortPlugins sorts plugins in place by the given key, in descending order
+// if desc is true. The sort is stable and ties are broken by slug in
+// ascending order, so results are deterministic across fetched pages.
+func SortPlugins(plugins []PluginInfo, by SortKey, desc bool) {
+	compare := sortCompareFunc(by)
+
+	slices.SortStableFunc(plugins, func(a, b PluginInfo) int {
+		c := compare(a, b)
+		if desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+		return strings.Compare(a.Slug, b.Slug)
+	})
+}
+
+func sortCompareFunc(by SortKey) func(a, b PluginInfo) int {
+	switch by {
+	case SortByActiveInstalls:
+		return func(a, b PluginInfo) int { return cmp.Compare(a.ActiveInstalls, b.ActiveInstalls) }
+	case SortByDownloaded:
+		return func(a, b PluginInfo) int { return cmp.Compare(a.Downloaded, b.Downloaded) }
+	case SortByRating:
+		return func(a, b PluginInfo) int { return cmp.Compare(a.Rating, b.Rating) }
+	case SortByNumRatings:
+		return func(a, b PluginInfo) int { return cmp.Compare(a.NumRatings, b.NumRatings) }
+	case SortByLastUpdated:
+		return func(a, b PluginInfo) int { return a.LastUpdated.Compare(b.LastUpdated.Time) }
+	default:
+		return func(a, b PluginInfo) int { return strings.Compare(a.Name, b.Name) }
+	}
+}
@@ -0,0 +1,90 @@
+package wordpress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func slugs(plugins []wordpress.PluginInfo) []string {
+	var s []string
+	for _, p := range plugins {
+		s = append(s, p.Slug)
+	}
+	return s
+}
+
+func TestSortPlugins(t *testing.T) {
+	day := func(d int) wordpress.Timestamp {
+		return wordpress.Timestamp{Time: time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)}
+	}
+
+	plugins := []wordpress.PluginInfo{
+		{Slug: "c", Name: "Charlie", ActiveInstalls: 100, Rating: 90, NumRatings: 10, Downloaded: 5, LastUpdated: day(3)},
+		{Slug: "a", Name: "Alpha", ActiveInstalls: 300, Rating: 80, NumRatings: 30, Downloaded: 5, LastUpdated: day(1)},
+		{Slug: "d", Name: "Delta", ActiveInstalls: 100, Rating: 95, NumRatings: 20, Downloaded: 9, LastUpdated: day(2)},
+		{Slug: "b", Name: "Bravo", ActiveInstalls: 200, Rating: 90, NumRatings: 40, Downloaded: 1, LastUpdated: day(4)},
+	}
+
+	tests := []struct {
+		name string
+		by   wordpress.SortKey
+		desc bool
+		want []string
+	}{
+		{
+			name: "name ascending",
+			by:   wordpress.SortByName,
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "active installs descending, ties by slug",
+			by:   wordpress.SortByActiveInstalls,
+			desc: true,
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "active installs ascending, ties by slug",
+			by:   wordpress.SortByActiveInstalls,
+			want: []string{"c", "d", "b", "a"},
+		},
+		{
+			name: "rating descending, ties by slug",
+			by:   wordpress.SortByRating,
+			desc: true,
+			want: []string{"d", "b", "c", "a"},
+		},
+		{
+			name: "downloaded descending, ties by slug",
+			by:   wordpress.SortByDownloaded,
+			desc: true,
+			want: []string{"d", "a", "c", "b"},
+		},
+		{
+			name: "num ratings ascending",
+			by:   wordpress.SortByNumRatings,
+			want: []string{"c", "d", "a", "b"},
+		},
+		{
+			name: "last updated descending",
+			by:   wordpress.SortByLastUpdated,
+			desc: true,
+			want: []string{"b", "c", "d", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := append([]wordpress.PluginInfo(nil), plugins...)
+			wordpress.SortPlugins(got, tt.by, tt.desc)
+
+			gotSlugs := slugs(got)
+			for i := range tt.want {
+				if gotSlugs[i] != tt.want[i] {
+					t.Fatalf("SortPlugins() = %v, want %v", gotSlugs, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package wordpress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// errStreamConsumed is yielded when a QueryPluginsStream sequence is
+// ranged over more than once
+var errStreamConsumed = errors.New("plugin stream already consumed")
+
+// QueryPluginsStream is like QueryPlugins but decodes the plugins one at a
+// time while the response is read, so a large page is never held in memory
+// as a whole and processing can start before it has fully arrived.
+//
+// The request is sent before QueryPluginsStream returns; errors yielded by
+// the sequence are decoding or API errors, or ErrClientClosed if Close was
+// called before ranging started. The sequence can be ranged over once and
+// must be, to release the connection; stopping early is fine. The
+// returned function reports the info block once it has been decoded, which
+// for WordPress.org responses is before the first plugin. Responses are
+// not cached.
+func (c *Client) QueryPluginsStream(ctx context.Context, browse string, perPage, page int, opts ...QueryOption) (iter.Seq2[PluginInfo, error], func() QueryInfo, error) {
+	if err := validateBrowse(browse); err != nil {
+		return nil, nil, err
+	}
+
+	query, err := queryPluginsParams(url.Values{"request[browse]": {browse}}, perPage, page, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := c.openAPI(ctx, "QueryPluginsStream", query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		info     QueryInfo
+		consumed bool
+	)
+	seq := func(yield func(PluginInfo, error) bool) {
+		if consumed {
+			yield(PluginInfo{}, errStreamConsumed)
+			return
+		}
+		consumed = true
+		defer body.Close()
+
+		// Close only waits for the stream while it is ranged over, so an
+		// abandoned sequence can't block it
+		done, err := c.begin()
+		if err != nil {
+			yield(PluginInfo{}, err)
+			return
+		}
+		defer done()
+
+		c.decodePluginStream(body, &info, yield)
+	}
+
+	return seq, func() QueryInfo { return info }, nil
+}
+
+// openAPI sends an API request with params and returns the decompressed
+// response body, failing over between base URLs like fetchAPI
+func (c *Client) openAPI(ctx context.Context, method string, params url.Values) (io.ReadCloser, error) {
+	if len(c.baseURLs) == 1 {
+		return c.openBody(ctx, method, fmt.Sprintf("%s?%s", c.baseURLs[0], params.Encode()))
+	}
+
+	var errs []error
+	for _, baseURL := range c.baseURLs {
+		body, err := c.openBody(ctx, method, fmt.Sprintf("%s?%s", baseURL, params.Encode()))
+		if err == nil || ctx.Err() != nil || !shouldFailover(err) {
+			return body, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", baseURL, err))
+	}
+
+	return nil, fmt.Errorf("all %d base URLs failed: %w", len(c.baseURLs), errors.Join(errs...))
+}
+
+// openBody is like getBody but returns the body unread
+func (c *Client) openBody(ctx context.Context, method, reqURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := c.do(method, req)
+	if err != nil {
+		return nil, c.requestError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	// QueryPluginsStream tracks the body while its sequence is ranged over
+	untrack(resp)
+
+	body, err := decodedBody(resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return &streamBody{Reader: body, closers: []io.Closer{body, resp.Body}}, nil
+}
+
+// streamBody closes both the decompressor and the response body
+type streamBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *streamBody) Close() error {
+	var errs []error
+	for _, c := range b.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// decodePluginStream decodes a query_plugins response from r, storing the
+// info block in info and yielding each plugin as soon as it is decoded
+func (c *Client) decodePluginStream(r io.Reader, info *QueryInfo, yield func(PluginInfo, error) bool) {
+	fail := func(err error) {
+		yield(PluginInfo{}, fmt.Errorf("%w: %w", ErrDecodeFailed, err))
+	}
+
+	dec := json.NewDecoder(r)
+	if c.strictDecoding {
+		dec.DisallowUnknownFields()
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		fail(err)
+		return
+	}
+	switch tok {
+	case json.Delim('{'):
+	case false, nil:
+		yield(PluginInfo{}, &APIError{StatusCode: http.StatusOK})
+		return
+	default:
+		fail(fmt.Errorf("unexpected %v at start of response", tok))
+		return
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		switch tok {
+		case "info":
+			if err := dec.Decode(info); err != nil {
+				fail(err)
+				return
+			}
+		case "plugins":
+			tok, err := dec.Token()
+			if err != nil {
+				fail(err)
+				return
+			}
+			// Some modes return an object keyed by slug, see
+			// QueryPluginsResponse.UnmarshalJSON
+			keyed := tok == json.Delim('{')
+			if !keyed && tok != json.Delim('[') {
+				fail(fmt.Errorf("plugins is neither an array nor an object"))
+				return
+			}
+			for dec.More() {
+				var slug string
+				if keyed {
+					key, err := dec.Token()
+					if err != nil {
+						fail(err)
+						return
+					}
+					slug, _ = key.(string)
+				}
+
+				var plugin PluginInfo
+				if err := dec.Decode(&plugin); err != nil {
+					fail(err)
+					return
+				}
+				if plugin.Slug == "" {
+					plugin.Slug = slug
+				}
+				if !yield(plugin, nil) {
+					return
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				fail(err)
+				return
+			}
+		case "error":
+			var message string
+			if err := dec.Decode(&message); err != nil {
+				fail(err)
+				return
+			}
+			yield(PluginInfo{}, &APIError{StatusCode: http.StatusOK, Message: message})
+			return
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				fail(err)
+				return
+			}
+		}
+	}
+}
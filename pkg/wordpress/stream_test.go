@@ -0,0 +1,75 @@
+package wordpress_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestClient_QueryPluginsStream(t *testing.T) {
+	const body = `{
+		"info": {"page": 2, "pages": 5, "results": 12},
+		"plugins": [
+			{"slug": "akismet", "name": "Akismet", "version": "5.3"},
+			{"slug": "jetpack", "name": "Jetpack", "version": "13.1"},
+			{"slug": "hello-dolly", "name": "Hello Dolly", "version": "1.7.2"}
+		]
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("request[page]") != "2" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("request[browse]") == wordpress.BrowseBeta {
+			w.Write([]byte(`{"error":"Invalid browse"}`))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+
+	plugins, info, err := client.QueryPluginsStream(context.Background(), wordpress.BrowsePopular, 3, 2)
+	if err != nil {
+		t.Fatalf("QueryPluginsStream() error = %v", err)
+	}
+
+	var slugs []string
+	for plugin, err := range plugins {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(slugs) == 0 && info().Pages != 5 {
+			t.Errorf("Expected the info block before the first plugin, got %+v", info())
+		}
+		slugs = append(slugs, plugin.Slug)
+	}
+
+	if want := []string{"akismet", "jetpack", "hello-dolly"}; !slices.Equal(slugs, want) {
+		t.Errorf("Streamed %v, want %v", slugs, want)
+	}
+	if got := info(); got != (wordpress.QueryInfo{Page: 2, Pages: 5, Results: 12}) {
+		t.Errorf("info() = %+v", got)
+	}
+
+	// An API error in the body is yielded
+	plugins, _, err = client.QueryPluginsStream(context.Background(), wordpress.BrowseBeta, 3, 2)
+	if err != nil {
+		t.Fatalf("QueryPluginsStream() error = %v", err)
+	}
+	var errs []error
+	for _, err := range plugins {
+		errs = append(errs, err)
+	}
+	var apiErr *wordpress.APIError
+	if len(errs) != 1 || !errors.As(errs[0], &apiErr) || apiErr.Message != "Invalid browse" {
+		t.Errorf("Expected a single APIError, got %v", errs)
+	}
+}
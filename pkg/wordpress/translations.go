@@ -0,0 +1,88 @@
+package wordpress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// translationUpdatedLayout is the format of the translations API's updated
+// field, e.g. "2024-03-01 10:22:41"
+const translationUpdatedLayout = "2006-01-02 15:04:05"
+
+// WithTranslationsBaseURL sets a custom base URL for the translations API
+// (mainly for testing)
+func WithTranslationsBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.translationsURL = baseURL
+	}
+}
+
+// Translation is a language pack available for a plugin version
+type Translation struct {
+	// Language is the WordPress locale, e.g. "de_DE"
+	Language   string
+	Version    string
+	PackageURL string
+	Updated    time.Time
+}
+
+// UnmarshalJSON implements custom unmarshaling for Translation
+func (t *Translation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Language string `json:"language"`
+		Version  string `json:"version"`
+		Package  string `json:"package"`
+		Updated  string `json:"updated"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = Translation{
+		Language:   raw.Language,
+		Version:    raw.Version,
+		PackageURL: raw.Package,
+	}
+	if raw.Updated != "" {
+		updated, err := time.Parse(translationUpdatedLayout, raw.Updated)
+		if err != nil {
+			return fmt.Errorf("cannot parse translation update time %q: %w", raw.Updated, err)
+		}
+		t.Updated = updated
+	}
+
+	return nil
+}
+
+// GetTranslations returns the translation packs available for a plugin
+// version. slug is normalized with NormalizeSlug. An empty version returns
+// the packs of the latest version.
+func (c *Client) GetTranslations(ctx context.Context, slug, version string) ([]Translation, error) {
+	slug, err := NormalizeSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("slug", slug)
+	if version != "" {
+		params.Set("version", version)
+	}
+
+	data, err := c.getBody(ctx, "GetTranslations", fmt.Sprintf("%s?%s", c.translationsURL, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Translations []Translation `json:"translations"`
+	}
+	if err := c.decodeJSON(data, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Translations, nil
+}
@@ -0,0 +1,76 @@
+package wordpress_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+const translationsPayload = `{
+  "translations": [
+    {
+      "language": "de_DE",
+      "version": "5.3",
+      "updated": "2024-03-01 10:22:41",
+      "english_name": "German",
+      "native_name": "Deutsch",
+      "package": "https://downloads.wordpress.org/translation/plugin/akismet/5.3/de_DE.zip",
+      "iso": {"1": "de"}
+    },
+    {
+      "language": "ja",
+      "version": "5.3",
+      "updated": "2024-02-12 03:01:09",
+      "english_name": "Japanese",
+      "native_name": "日本語",
+      "package": "https://downloads.wordpress.org/translation/plugin/akismet/5.3/ja.zip",
+      "iso": {"1": "ja"}
+    }
+  ]
+}`
+
+func TestClient_GetTranslations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("slug") != "akismet" || q.Get("version") != "5.3" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(translationsPayload))
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(wordpress.WithTranslationsBaseURL(server.URL))
+
+	translations, err := client.GetTranslations(context.Background(), "akismet", "5.3")
+	if err != nil {
+		t.Fatalf("GetTranslations() error = %v", err)
+	}
+	if len(translations) != 2 {
+		t.Fatalf("Expected 2 translations, got %d", len(translations))
+	}
+
+	want := wordpress.Translation{
+		Language:   "de_DE",
+		Version:    "5.3",
+		PackageURL: "https://downloads.wordpress.org/translation/plugin/akismet/5.3/de_DE.zip",
+		Updated:    time.Date(2024, 3, 1, 10, 22, 41, 0, time.UTC),
+	}
+	if translations[0] != want {
+		t.Errorf("translations[0] = %+v, want %+v", translations[0], want)
+	}
+	if translations[1].Language != "ja" {
+		t.Errorf("Expected ja, got %q", translations[1].Language)
+	}
+
+	// Invalid slugs are rejected before a request is made
+	for _, slug := range []string{"", "../x"} {
+		if _, err := client.GetTranslations(context.Background(), slug, "5.3"); err == nil {
+			t.Errorf("GetTranslations(%q): expected an invalid slug error", slug)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package wordpress_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// recordingTransport records the URL of every request before delegating to
+// the default transport
+type recordingTransport struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.urls = append(rt.urls, req.URL.String())
+	rt.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClient_WithTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer server.Close()
+
+	rt := &recordingTransport{}
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithTransport(rt),
+		wordpress.WithTimeout(5*time.Second),
+	)
+
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+
+	if len(rt.urls) != 1 {
+		t.Fatalf("Expected 1 recorded request, got %d", len(rt.urls))
+	}
+}
+
+func TestClient_WithTransportAndTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	rt := &recordingTransport{}
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithTransport(rt),
+		wordpress.WithTimeout(20*time.Millisecond),
+	)
+
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); err == nil {
+		t.Fatal("Expected timeout error")
+	}
+	if len(rt.urls) != 1 {
+		t.Errorf("Expected the request to go through the transport, got %d requests", len(rt.urls))
+	}
+}
+
+func TestClient_WithRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("request[slug]") == "slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithRequestTimeout(20*time.Millisecond),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.GetPluginInfo(ctx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the request to time out, got %v", err)
+	}
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("Expected the parent context to stay alive, got %v", err)
+	}
+
+	// The same context is still usable for the next call
+	if _, err := client.GetPluginInfo(ctx, "akismet"); err != nil {
+		t.Errorf("GetPluginInfo() error = %v", err)
+	}
+}
+
+func TestClient_WithHTTPClientTakesPrecedence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer server.Close()
+
+	ignored := &recordingTransport{}
+	used := &recordingTransport{}
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithTransport(ignored),
+		wordpress.WithHTTPClient(&http.Client{Transport: used}),
+	)
+
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+
+	if len(ignored.urls) != 0 || len(used.urls) != 1 {
+		t.Errorf("Expected only the HTTP client's transport to be used, got ignored=%d used=%d", len(ignored.urls), len(used.urls))
+	}
+}
+
+func TestClient_WithHeader(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer server.Close()
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithUserAgent("wp-detector/1.0"),
+		wordpress.WithHeader("X-Trace-Id", "abc"),
+		wordpress.WithHeader("X-Tag", "one"),
+		wordpress.WithHeader("x-tag", "two"),
+		wordpress.WithHeader("User-Agent", "ignored"),
+		wordpress.WithHeader("Accept-Encoding", "br"),
+	)
+
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+
+	if v := got.Get("X-Trace-Id"); v != "abc" {
+		t.Errorf("Expected X-Trace-Id abc, got %q", v)
+	}
+	if v := got.Values("X-Tag"); !slices.Equal(v, []string{"one", "two"}) {
+		t.Errorf("Expected X-Tag values [one two], got %v", v)
+	}
+	if v := got.Get("User-Agent"); v != "wp-detector/1.0" {
+		t.Errorf("Expected User-Agent from WithUserAgent, got %q", v)
+	}
+	if v := got.Get("Accept-Encoding"); v != "gzip" {
+		t.Errorf("Expected the client's Accept-Encoding to be kept, got %q", v)
+	}
+}
+
+func TestClient_WithProxy(t *testing.T) {
+	var (
+		gotHost string
+		gotAuth string
+	)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		gotAuth = r.Header.Get("Proxy-Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer proxy.Close()
+
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:secret@", 1)
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL("http://api.wordpress.test/plugins/info/1.2/"),
+		wordpress.WithTransport(&http.Transport{}),
+		wordpress.WithTimeout(5*time.Second),
+		wordpress.WithProxy(proxyURL),
+	)
+
+	info, err := client.GetPluginInfo(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if info.Slug != "akismet" {
+		t.Errorf("Expected slug akismet, got %s", info.Slug)
+	}
+	if gotHost != "api.wordpress.test" {
+		t.Errorf("Expected the request for api.wordpress.test to reach the proxy, got host %q", gotHost)
+	}
+	wantAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	if gotAuth != wantAuth {
+		t.Errorf("Expected Proxy-Authorization %q, got %q", wantAuth, gotAuth)
+	}
+}
+
+func TestClient_WithProxy_Invalid(t *testing.T) {
+	for _, proxyURL := range []string{"://bad", "ftp://proxy.example.com", "proxy.example.com:8080"} {
+		client := wordpress.NewClient(wordpress.WithProxy(proxyURL))
+
+		if _, err := client.GetPluginInfo(context.Background(), "akismet"); err == nil || !strings.Contains(err.Error(), "invalid proxy") {
+			t.Errorf("WithProxy(%q): expected invalid proxy error, got %v", proxyURL, err)
+		}
+	}
+}
+
+func TestClient_WithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(wordpress.PluginInfo{Slug: "akismet"})
+	}))
+	defer server.Close()
+
+	// The self-signed certificate isn't trusted by default
+	untrusted := wordpress.NewClient(wordpress.WithBaseURL(server.URL))
+	if _, err := untrusted.GetPluginInfo(context.Background(), "akismet"); err == nil {
+		t.Fatal("Expected certificate verification to fail without the custom CA")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	client := wordpress.NewClient(
+		wordpress.WithBaseURL(server.URL),
+		wordpress.WithTransport(&http.Transport{}),
+		wordpress.WithTimeout(5*time.Second),
+		wordpress.WithTLSConfig(&tls.Config{RootCAs: pool}),
+	)
+
+	info, err := client.GetPluginInfo(context.Background(), "akismet")
+	if err != nil {
+		t.Fatalf("GetPluginInfo() error = %v", err)
+	}
+	if info.Slug != "akismet" {
+		t.Errorf("Expected slug akismet, got %s", info.Slug)
+	}
+}
+
+func TestClient_WithConnectionPool(t *testing.T) {
+	base := &http.Transport{MaxIdleConns: 100}
+	client := wordpress.NewClient(
+		wordpress.WithTransport(base),
+		wordpress.WithConnectionPool(20, 4),
+	)
+
+	transport, ok := client.Transport().(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport, got %T", client.Transport())
+	}
+	if transport.MaxIdleConns != 20 || transport.MaxIdleConnsPerHost != 4 || transport.MaxConnsPerHost != 4 {
+		t.Errorf("Unexpected pool settings: MaxIdleConns=%d MaxIdleConnsPerHost=%d MaxConnsPerHost=%d",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost)
+	}
+	if base.MaxIdleConns != 100 {
+		t.Error("Expected the given transport to be left unmodified")
+	}
+
+	// A transport that can't be tuned makes every request fail
+	client = wordpress.NewClient(
+		wordpress.WithTransport(roundTripperFunc(http.DefaultTransport.RoundTrip)),
+		wordpress.WithConnectionPool(20, 4),
+	)
+	if _, err := client.GetPluginInfo(context.Background(), "akismet"); err == nil || !strings.Contains(err.Error(), "WithConnectionPool") {
+		t.Errorf("Expected a transport error, got %v", err)
+	}
+}
+
+func TestClient_WithDisableHTTP2(t *testing.T) {
+	client := wordpress.NewClient(wordpress.WithDisableHTTP2())
+
+	transport, ok := client.Transport().(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport, got %T", client.Transport())
+	}
+	if transport.ForceAttemptHTTP2 {
+		t.Error("Expected ForceAttemptHTTP2 to be false")
+	}
+	if transport.TLSNextProto == nil || len(transport.TLSNextProto) != 0 {
+		t.Errorf("Expected an empty non-nil TLSNextProto, got %v", transport.TLSNextProto)
+	}
+}
@@ -0,0 +1,111 @@
+package wordpress
+
+import (
+	"math"
+	"time"
+)
+
+// Weights of the signals combined by TrustScore. They add up to 100.
+const (
+	trustWeightInstalls = 35
+	trustWeightRating   = 30
+	trustWeightSupport  = 15
+	trustWeightRecency  = 20
+)
+
+// Thresholds used by TrustScore
+const (
+	// trustFullInstalls is the number of active installs that earns the
+	// full installs weight. Installs are scored on a log scale.
+	trustFullInstalls = 1_000_000
+	// trustFullRatings is the number of ratings needed for the rating to
+	// count fully; fewer ratings scale it down
+	trustFullRatings = 20
+	// trustNeutralSupport is the support score of a plugin without recent
+	// support threads
+	trustNeutralSupport = 0.5
+	// Plugins updated within trustFreshAge get the full recency weight,
+	// plugins older than trustStaleAge none, with a linear decay between.
+	// A plugin that releases less often is fresh for its average release
+	// interval instead, up to trustMaxFreshAge.
+	trustFreshAge    = 90 * 24 * time.Hour
+	trustMaxFreshAge = 365 * 24 * time.Hour
+	trustStaleAge    = 2 * 365 * 24 * time.Hour
+)
+
+// TrustScore returns a 0-100 heuristic of how trustworthy a plugin is, for
+// ranking recommendations. It is TrustScoreAt the current time.
+func (p PluginInfo) TrustScore() float64 {
+	return p.TrustScoreAt(time.Now())
+}
+
+// TrustScoreAt returns the trust score of the plugin as of now. The score
+// is weighted as follows:
+//
+//   - 35: active installs, on a log scale up to 1,000,000
+//   - 30: rating, scaled down when there are fewer than 20 ratings
+//   - 15: support resolution rate, half when there were no threads
+//   - 20: recency of LastUpdated, full within 90 days or the average
+//     interval between releases reported by ReleaseCadence, up to a year,
+//     and none after 2 years
+func (p PluginInfo) TrustScoreAt(now time.Time) float64 {
+	installs := 0.0
+	if p.ActiveInstalls > 0 {
+		installs = math.Log10(float64(p.ActiveInstalls)) / math.Log10(trustFullInstalls)
+	}
+
+	rating := float64(p.Rating) / 100 * min(float64(p.NumRatings)/trustFullRatings, 1)
+
+	support := trustNeutralSupport
+	if p.SupportThreads > 0 {
+		support = p.SupportResolutionRate()
+	}
+
+	return trustWeightInstalls*clamp01(installs) +
+		trustWeightRating*clamp01(rating) +
+		trustWeightSupport*clamp01(support) +
+		trustWeightRecency*clamp01(p.recencyAt(now))
+}
+
+// recencyAt scores how recently the plugin was updated as of now, given how
+// often ReleaseCadence says it releases
+func (p PluginInfo) recencyAt(now time.Time) float64 {
+	if p.LastUpdated.IsZero() {
+		return 0
+	}
+
+	fresh := trustFreshAge
+	if releases, span := p.ReleaseCadence(); releases > 1 && span > 0 {
+		interval := span / time.Duration(releases-1)
+		fresh = min(max(fresh, interval), trustMaxFreshAge)
+	}
+
+	age := now.Sub(p.LastUpdated.Time)
+	return 1 - float64(age-fresh)/float64(trustStaleAge-fresh)
+}
+
+func clamp01(f float64) float64 {
+	return max(0, min(f, 1))
+}
+
+// ReleaseCadence returns the number of released versions in Versions,
+// excluding trunk, and the time between the plugin being added and its last
+// update, which roughly covers those releases. Without Versions, which must
+// be requested with WithQueryFields(QueryFields{"versions": true}), the
+// current version counts as the only release. span is zero when either date
+// is unknown.
+func (p PluginInfo) ReleaseCadence() (releases int, span time.Duration) {
+	for version := range p.Versions {
+		if version != "trunk" {
+			releases++
+		}
+	}
+	if releases == 0 && p.Version != "" {
+		releases = 1
+	}
+
+	if !p.Added.IsZero() && !p.LastUpdated.IsZero() && p.LastUpdated.After(p.Added.Time) {
+		span = p.LastUpdated.Sub(p.Added.Time)
+	}
+	return releases, span
+}
@@ -0,0 +1,143 @@
+package wordpress_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestPluginInfo_TrustScoreAt(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	updated := func(d time.Duration) wordpress.Timestamp {
+		return wordpress.Timestamp{Time: now.Add(-d)}
+	}
+
+	tests := []struct {
+		name    string
+		plugin  wordpress.PluginInfo
+		wantMin float64
+		wantMax float64
+	}{
+		{
+			name: "popular and maintained",
+			plugin: wordpress.PluginInfo{
+				ActiveInstalls: 5000000, Rating: 96, NumRatings: 1200,
+				SupportThreads: 50, SupportThreadsResolved: 45,
+				LastUpdated: updated(7 * 24 * time.Hour),
+			},
+			wantMin: 95,
+			wantMax: 100,
+		},
+		{
+			name: "stale with few installs",
+			plugin: wordpress.PluginInfo{
+				ActiveInstalls: 10, Rating: 60, NumRatings: 2,
+				SupportThreads: 4,
+				LastUpdated:    updated(3 * 365 * 24 * time.Hour),
+			},
+			wantMin: 0,
+			wantMax: 10,
+		},
+		{
+			name:    "no data",
+			plugin:  wordpress.PluginInfo{},
+			wantMin: 7.5,
+			wantMax: 7.5,
+		},
+		{
+			name: "half way to stale",
+			plugin: wordpress.PluginInfo{
+				ActiveInstalls: 1000, Rating: 90, NumRatings: 10,
+				// Half way between fresh (90 days) and stale (730 days)
+				LastUpdated: updated(410 * 24 * time.Hour),
+			},
+			wantMin: 48.5,
+			wantMax: 48.5,
+		},
+		{
+			name: "yearly releases",
+			plugin: wordpress.PluginInfo{
+				ActiveInstalls: 1000, Rating: 90, NumRatings: 10,
+				// Three releases over two years make a year fresh, so 410
+				// days is 45 days into the 365 day decay
+				Added:       wordpress.Date{Time: now.Add(-(410 + 730) * 24 * time.Hour)},
+				LastUpdated: updated(410 * 24 * time.Hour),
+				Versions:    wordpress.Versions{"1.0": "", "2.0": "", "3.0": "", "trunk": ""},
+			},
+			wantMin: 56,
+			wantMax: 56.1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.plugin.TrustScoreAt(now)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("TrustScoreAt() = %.2f, want between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+			if again := tt.plugin.TrustScoreAt(now); again != got {
+				t.Errorf("TrustScoreAt() is not deterministic: %v != %v", got, again)
+			}
+		})
+	}
+}
+
+func TestPluginInfo_ReleaseCadence(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      string
+		wantReleases int
+		wantSpan     time.Duration
+	}{
+		{
+			name: "versions and dates",
+			payload: `{
+				"slug": "hello-dolly",
+				"version": "1.7.2",
+				"added": "2024-01-01",
+				"last_updated": "2024-01-31 12:00am GMT",
+				"versions": {
+					"1.6": "https://downloads.wordpress.org/plugin/hello-dolly.1.6.zip",
+					"1.7.1": "https://downloads.wordpress.org/plugin/hello-dolly.1.7.1.zip",
+					"1.7.2": "https://downloads.wordpress.org/plugin/hello-dolly.1.7.2.zip",
+					"trunk": "https://downloads.wordpress.org/plugin/hello-dolly.zip"
+				}
+			}`,
+			wantReleases: 3,
+			wantSpan:     30 * 24 * time.Hour,
+		},
+		{
+			name: "versions without dates",
+			payload: `{
+				"slug": "hello-dolly",
+				"versions": {"1.6": "", "1.7.2": ""}
+			}`,
+			wantReleases: 2,
+		},
+		{
+			name:         "no versions requested",
+			payload:      `{"slug": "hello-dolly", "version": "1.7.2", "added": "2024-01-01"}`,
+			wantReleases: 1,
+		},
+		{
+			name:    "nothing known",
+			payload: `{"slug": "hello-dolly", "versions": []}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p wordpress.PluginInfo
+			if err := json.Unmarshal([]byte(tt.payload), &p); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			releases, span := p.ReleaseCadence()
+			if releases != tt.wantReleases || span != tt.wantSpan {
+				t.Errorf("ReleaseCadence() = %d, %v, want %d, %v", releases, span, tt.wantReleases, tt.wantSpan)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package wordpress
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Version is a parsed plugin, core or PHP version such as "1.2.3" or
+// "2.0-beta1". Versions are ordered the way CompareVersions orders their
+// strings; the zero value orders below every parsed version.
+type Version struct {
+	raw   string
+	parts []string
+}
+
+// ParseVersion parses s, which must start with a number. String returns s
+// unchanged.
+func ParseVersion(s string) (Version, error) {
+	parts := canonicalVersionParts(s)
+	if len(parts) == 0 {
+		return Version{}, fmt.Errorf("invalid version %q: empty", s)
+	}
+	if _, err := strconv.Atoi(parts[0]); err != nil {
+		return Version{}, fmt.Errorf("invalid version %q: must start with a number", s)
+	}
+	return Version{raw: s, parts: parts}, nil
+}
+
+// Compare returns -1 if v < other, 0 if they are equal and 1 if v > other.
+// Equal versions may be spelled differently, e.g. "2.0RC1" and "2.0-rc1".
+func (v Version) Compare(other Version) int {
+	return compareVersionParts(v.parts, other.parts)
+}
+
+// String returns the version as it was given to ParseVersion
+func (v Version) String() string {
+	return v.raw
+}
+
+// IsOutdated reports whether installed is older than latest. It is false
+// when either version can't be parsed, since nothing is known then.
+func IsOutdated(installed, latest string) bool {
+	iv, err := ParseVersion(installed)
+	if err != nil {
+		return false
+	}
+	lv, err := ParseVersion(latest)
+	if err != nil {
+		return false
+	}
+	return iv.Compare(lv) < 0
+}
+
+// CompareVersions compares two version strings the way PHP's
+// version_compare does, which is what WordPress uses for plugin, core and
+// PHP versions. It returns -1 if a < b, 0 if a == b and 1 if a > b.
+//
+// Pre-release suffixes order below their release, e.g.
+// "2.0-dev" < "2.0-alpha" < "2.0-beta1" < "2.0RC1" < "2.0" < "2.0pl1".
+func CompareVersions(a, b string) int {
+	return compareVersionParts(canonicalVersionParts(a), canonicalVersionParts(b))
+}
+
+func compareVersionParts(pa, pb []string) int {
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var c int
+		switch {
+		case i >= len(pa):
+			c = -compareMissingVersionPart(pb[i])
+		case i >= len(pb):
+			c = compareMissingVersionPart(pa[i])
+		default:
+			c = compareVersionPart(pa[i], pb[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// canonicalVersionParts splits a version into its components, treating
+// "-", "_" and "+" as separators and splitting between digits and letters
+// ("1.0rc1" -> ["1", "0", "rc", "1"])
+func canonicalVersionParts(v string) []string {
+	var parts []string
+	var cur strings.Builder
+	var curDigit bool
+
+	flush := func() {
+		if cur.Len() > 0 {
+			parts = append(parts, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, r := range strings.TrimSpace(v) {
+		switch {
+		case r == '.' || r == '-' || r == '_' || r == '+':
+			flush()
+		default:
+			isDigit := unicode.IsDigit(r)
+			if cur.Len() > 0 && isDigit != curDigit {
+				flush()
+			}
+			curDigit = isDigit
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+
+	return parts
+}
+
+// specialVersionOrder ranks the non-numeric components PHP recognizes. "#"
+// stands for any number; unknown strings rank below "dev".
+func specialVersionOrder(s string) int {
+	switch strings.ToLower(s) {
+	case "dev":
+		return 0
+	case "alpha", "a":
+		return 1
+	case "beta", "b":
+		return 2
+	case "rc", "c":
+		return 3
+	case "#":
+		return 4
+	case "pl", "p":
+		return 5
+	default:
+		return -1
+	}
+}
+
+func compareVersionPart(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		a = "#"
+	case errB == nil:
+		b = "#"
+	}
+
+	return cmp.Compare(specialVersionOrder(a), specialVersionOrder(b))
+}
+
+// compareMissingVersionPart compares a trailing component against a version
+// that has run out of components: any number makes the longer version
+// greater ("1.0.1" > "1.0"), while a suffix is compared as if the shorter
+// version had a number there ("1.0-beta" < "1.0")
+func compareMissingVersionPart(part string) int {
+	if _, err := strconv.Atoi(part); err == nil {
+		return 1
+	}
+	return compareVersionPart(part, "#")
+}
@@ -0,0 +1,155 @@
+package wordpress_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", -1},
+		{"1.2.3", "1.2.10", -1},
+		{"2.0", "1.9.9", 1},
+		{"7.4", "8.0", -1},
+		{"2.0-beta1", "2.0", -1},
+		{"2.0-alpha", "2.0-beta", -1},
+		{"2.0-dev", "2.0-alpha", -1},
+		{"2.0RC1", "2.0-rc1", 0},
+		{"2.0-rc1", "2.0", -1},
+		{"2.0pl1", "2.0", 1},
+		{"2.0-beta2", "2.0-beta10", -1},
+		{"1.0", "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			if got := wordpress.CompareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := wordpress.CompareVersions(tt.b, tt.a); got != -tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{input: "1.2.3"},
+		{input: "1.2"},
+		{input: "2.0-beta1"},
+		{input: " 6.4.2 "},
+		{input: "2.0RC1"},
+		{input: "", wantErr: true},
+		{input: "...", wantErr: true},
+		{input: "trunk", wantErr: true},
+		{input: "v1.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := wordpress.ParseVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && v.String() != tt.input {
+				t.Errorf("String() = %q, want %q", v.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", -1},
+		{"1.2.3", "1.2.10", -1},
+		{"1.10", "1.9", 1},
+		{"2.0", "1.9.9", 1},
+		{"2.0-beta", "2.0", -1},
+		{"2.0-beta1", "2.0", -1},
+		{"2.0-beta", "2.0-beta1", -1},
+		{"2.0-beta2", "2.0-beta10", -1},
+		{"2.0-dev", "2.0-alpha", -1},
+		{"2.0-alpha", "2.0-beta", -1},
+		{"2.0-beta", "2.0-rc1", -1},
+		{"2.0RC1", "2.0-rc1", 0},
+		{"2.0-rc1", "2.0", -1},
+		{"2.0", "2.0pl1", -1},
+		{"2.0", "2.0.1-beta", -1},
+		{"1.9.9", "2.0-dev", -1},
+		{"2.0_1", "2.0.1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			a, err := wordpress.ParseVersion(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := wordpress.ParseVersion(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := b.Compare(a); got != -tt.want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestVersion_Sort(t *testing.T) {
+	input := []string{"2.0", "1.10", "2.0-beta1", "1.9", "2.0-rc1", "2.0-alpha", "2.0.1"}
+	want := []string{"1.9", "1.10", "2.0-alpha", "2.0-beta1", "2.0-rc1", "2.0", "2.0.1"}
+
+	var versions []wordpress.Version
+	for _, s := range input {
+		v, err := wordpress.ParseVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, v)
+	}
+	slices.SortFunc(versions, wordpress.Version.Compare)
+
+	for i, v := range versions {
+		if v.String() != want[i] {
+			t.Errorf("versions[%d] = %s, want %s", i, v, want[i])
+		}
+	}
+}
+
+func TestIsOutdated(t *testing.T) {
+	tests := []struct {
+		installed, latest string
+		want              bool
+	}{
+		{"5.3", "5.5", true},
+		{"5.5", "5.5", false},
+		{"6.1.10", "6.1.3", false},
+		{"2.0-beta1", "2.0", true},
+		{"", "5.5", false},
+		{"5.3", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := wordpress.IsOutdated(tt.installed, tt.latest); got != tt.want {
+			t.Errorf("IsOutdated(%q, %q) = %v, want %v", tt.installed, tt.latest, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package wordpress
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrInvalidPluginZip is returned when an archive doesn't look like a
+// WordPress plugin
+var ErrInvalidPluginZip = errors.New("invalid plugin zip")
+
+// ErrNotAZip is returned when a download is not a ZIP archive, typically an
+// HTML error page served with status 200
+var ErrNotAZip = errors.New("response is not a zip archive")
+
+// ArchiveType is the kind of data a download turned out to hold
+type ArchiveType string
+
+const (
+	ArchiveZip  ArchiveType = "zip"
+	ArchiveGzip ArchiveType = "gzip"
+	// ArchivePlain is anything that isn't a known archive format, such as
+	// an HTML error page
+	ArchivePlain ArchiveType = "plain"
+)
+
+var (
+	// zipMagic is the local file header signature every ZIP archive with
+	// files starts with
+	zipMagic = []byte("PK\x03\x04")
+	// emptyZipMagic is the end of central directory signature an empty ZIP
+	// archive starts with
+	emptyZipMagic = []byte("PK\x05\x06")
+	gzipMagic     = []byte{0x1f, 0x8b}
+)
+
+// DetectArchiveType tells the archive format of data from its magic bytes.
+// Only the first few bytes are looked at. It fails on empty data.
+func DetectArchiveType(data []byte) (ArchiveType, error) {
+	switch {
+	case len(data) == 0:
+		return "", fmt.Errorf("cannot detect the archive type of empty data")
+	case bytes.HasPrefix(data, zipMagic), bytes.HasPrefix(data, emptyZipMagic):
+		return ArchiveZip, nil
+	case bytes.HasPrefix(data, gzipMagic):
+		return ArchiveGzip, nil
+	default:
+		return ArchivePlain, nil
+	}
+}
+
+// ValidatePluginZip checks that data is a ZIP archive containing a top-level
+// directory named expectedSlug with at least one PHP file declaring a plugin
+// header. It rejects empty archives and HTML error pages served in place of
+// the archive.
+func ValidatePluginZip(data []byte, expectedSlug string) error {
+	return ValidatePluginZipReader(bytes.NewReader(data), int64(len(data)), expectedSlug)
+}
+
+// ValidatePluginZipReader is like ValidatePluginZip but reads the archive
+// from r, so large archives don't have to be held in memory
+func ValidatePluginZipReader(r io.ReaderAt, size int64, expectedSlug string) error {
+	if size == 0 {
+		return fmt.Errorf("%w: empty archive", ErrInvalidPluginZip)
+	}
+
+	head := make([]byte, min(size, 512))
+	if _, err := r.ReadAt(head, 0); err != nil && err != io.EOF {
+		return fmt.Errorf("%w: %v", ErrInvalidPluginZip, err)
+	}
+	if looksLikeHTML(head) {
+		return fmt.Errorf("%w: received an HTML document", ErrInvalidPluginZip)
+	}
+	switch typ, _ := DetectArchiveType(head); typ {
+	case ArchiveGzip:
+		return fmt.Errorf("%w: received a gzip archive instead of a ZIP", ErrInvalidPluginZip)
+	case ArchivePlain:
+		return fmt.Errorf("%w: received data that isn't an archive", ErrInvalidPluginZip)
+	}
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPluginZip, err)
+	}
+	if len(zr.File) == 0 {
+		return fmt.Errorf("%w: archive has no files", ErrInvalidPluginZip)
+	}
+
+	foundDir := false
+	for _, file := range zr.File {
+		dir, rest, ok := strings.Cut(file.Name, "/")
+		if !ok || dir != expectedSlug {
+			continue
+		}
+		foundDir = true
+
+		// Plugin headers are only read from files directly inside the
+		// plugin directory
+		if strings.Contains(rest, "/") || path.Ext(rest) != ".php" {
+			continue
+		}
+
+		if hasPluginHeader(file) {
+			return nil
+		}
+	}
+
+	if !foundDir {
+		return fmt.Errorf("%w: no top-level directory %q", ErrInvalidPluginZip, expectedSlug)
+	}
+	return fmt.Errorf("%w: no plugin header found in %s/*.php", ErrInvalidPluginZip, expectedSlug)
+}
+
+func hasPluginHeader(file *zip.File) bool {
+	rc, err := file.Open()
+	if err != nil {
+		return false
+	}
+	defer rc.Close()
+
+	_, err = ReadPluginHeader(rc)
+	return err == nil
+}
+
+func looksLikeHTML(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 512 {
+		trimmed = trimmed[:512]
+	}
+	lower := bytes.ToLower(trimmed)
+	return bytes.HasPrefix(lower, []byte("<!doctype html")) ||
+		bytes.HasPrefix(lower, []byte("<html")) ||
+		bytes.HasPrefix(lower, []byte("<?xml"))
+}
@@ -0,0 +1,161 @@
+package wordpress_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/masahiro331/go-wp-detector/pkg/wordpress"
+)
+
+// buildZip creates an in-memory ZIP archive from a map of file names to
+// contents
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestValidatePluginZip(t *testing.T) {
+	validHeader := `<?php
+/**
+ * Plugin Name: Hello Dolly
+ * Version: 1.7.2
+ */`
+
+	tests := []struct {
+		name    string
+		data    []byte
+		slug    string
+		wantErr bool
+	}{
+		{
+			name: "valid plugin zip",
+			data: buildZip(t, map[string]string{
+				"hello-dolly/hello.php":  validHeader,
+				"hello-dolly/readme.txt": "=== Hello Dolly ===",
+			}),
+			slug:    "hello-dolly",
+			wantErr: false,
+		},
+		{
+			name: "zip containing an HTML error page",
+			data: buildZip(t, map[string]string{
+				"index.html": "<html><body>502 Bad Gateway</body></html>",
+			}),
+			slug:    "hello-dolly",
+			wantErr: true,
+		},
+		{
+			name:    "raw HTML instead of a zip",
+			data:    []byte("<!DOCTYPE html><html><body>Not Found</body></html>"),
+			slug:    "hello-dolly",
+			wantErr: true,
+		},
+		{
+			name:    "empty data",
+			data:    nil,
+			slug:    "hello-dolly",
+			wantErr: true,
+		},
+		{
+			name:    "empty archive",
+			data:    buildZip(t, map[string]string{}),
+			slug:    "hello-dolly",
+			wantErr: true,
+		},
+		{
+			name: "slug mismatch",
+			data: buildZip(t, map[string]string{
+				"other/hello.php": validHeader,
+			}),
+			slug:    "hello-dolly",
+			wantErr: true,
+		},
+		{
+			name: "header only in nested file",
+			data: buildZip(t, map[string]string{
+				"hello-dolly/includes/hello.php": validHeader,
+			}),
+			slug:    "hello-dolly",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wordpress.ValidatePluginZip(tt.data, tt.slug)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatePluginZip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, wordpress.ErrInvalidPluginZip) {
+				t.Errorf("Expected ErrInvalidPluginZip, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDetectArchiveType(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte("not a zip"))
+	zw.Close()
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    wordpress.ArchiveType
+		wantErr bool
+	}{
+		{name: "zip", data: buildZip(t, map[string]string{"hello-dolly/hello.php": "<?php"}), want: wordpress.ArchiveZip},
+		{name: "empty zip", data: buildZip(t, nil), want: wordpress.ArchiveZip},
+		{name: "gzip", data: gz.Bytes(), want: wordpress.ArchiveGzip},
+		{name: "random bytes", data: []byte{0x13, 0x37, 0xde, 0xad, 0xbe, 0xef}, want: wordpress.ArchivePlain},
+		{name: "html", data: []byte("<html><body>Not Found</body></html>"), want: wordpress.ArchivePlain},
+		{name: "short", data: []byte("P"), want: wordpress.ArchivePlain},
+		{name: "empty", data: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := wordpress.DetectArchiveType(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectArchiveType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DetectArchiveType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePluginZip_Gzip(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte("hello-dolly/hello.php"))
+	zw.Close()
+
+	err := wordpress.ValidatePluginZip(gz.Bytes(), "hello-dolly")
+	if !errors.Is(err, wordpress.ErrInvalidPluginZip) || !strings.Contains(err.Error(), "gzip") {
+		t.Errorf("Expected an error naming the gzip archive, got %v", err)
+	}
+}